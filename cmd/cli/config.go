@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant"
 	"io"
 	"io/ioutil"
@@ -30,10 +31,20 @@ type Config struct {
 	Environment                      string   `json:"environment"`
 	Brand                            string   `json:"brand"`
 	PreferredTokenEndPointAuthMethod string   `json:"preferred_token_endpoint_auth_method"`
+	PreferredSigningAlg              string   `json:"preferred_signing_alg"`
+	JwksURI                          string   `json:"jwks_uri"`
 	CreateSoftwareClientOnly         bool     `json:"create_software_client_only"`
 	AuthorizationSignedResponseAlg   string   `json:"authorization_signed_response_alg"`
+	SigningKeyFile                   string   `json:"private_key_file"`
+	TransportRootCAsFiles            []string `json:"transport_root_cas_files"`
+	TransportCertFile                string   `json:"transport_cert_file"`
+	TransportKeyFile                 string   `json:"transport_key_file"`
+	TransportPKCS12File              string   `json:"transport_pkcs12_file"`
 }
 
+// keyPassphraseEnv is the environment variable holding the passphrase of encrypted private keys and PKCS#12 bundles
+const keyPassphraseEnv = "DCR_KEY_PASSPHRASE"
+
 func LoadConfig(configFilePath string) (Config, error) {
 	f, err := os.Open(configFilePath)
 	if err != nil {
@@ -46,6 +57,11 @@ func LoadConfig(configFilePath string) (Config, error) {
 		return Config{}, errors.Wrap(err, "load config")
 	}
 
+	config, err = loadKeyMaterial(config, os.Getenv(keyPassphraseEnv))
+	if err != nil {
+		return Config{}, errors.Wrap(err, "load config")
+	}
+
 	err = validateConfig(config)
 	if err != nil {
 		return Config{}, errors.Wrap(err, "load config")
@@ -81,3 +97,81 @@ func validateConfig(config Config) error {
 	}
 	return nil
 }
+
+// loadKeyMaterial reads the keys and certificates set as file paths into their PEM properties, unpacks the transport
+// PKCS#12 bundle and decrypts encrypted private keys with the passphrase
+func loadKeyMaterial(config Config, passphrase string) (Config, error) {
+	var err error
+	config.SigningKeyPEM, err = pemFromFile(config.SigningKeyPEM, config.SigningKeyFile, "private_key")
+	if err != nil {
+		return Config{}, err
+	}
+	config.TransportCertPEM, err = pemFromFile(config.TransportCertPEM, config.TransportCertFile, "transport_cert")
+	if err != nil {
+		return Config{}, err
+	}
+	config.TransportKeyPEM, err = pemFromFile(config.TransportKeyPEM, config.TransportKeyFile, "transport_key")
+	if err != nil {
+		return Config{}, err
+	}
+	for _, path := range config.TransportRootCAsFiles {
+		var rootCA []byte
+		rootCA, err = ioutil.ReadFile(path)
+		if err != nil {
+			return Config{}, errors.Wrap(err, "reading transport_root_cas_files")
+		}
+		config.TransportRootCAsPEM = append(config.TransportRootCAsPEM, string(rootCA))
+	}
+
+	if config.TransportPKCS12File != "" {
+		if config.TransportCertPEM != "" || config.TransportKeyPEM != "" {
+			return Config{}, errors.New("transport_pkcs12_file can't be combined with transport_cert and transport_key")
+		}
+		var bundle, keyPEM, chainPEM []byte
+		bundle, err = ioutil.ReadFile(config.TransportPKCS12File)
+		if err != nil {
+			return Config{}, errors.Wrap(err, "reading transport_pkcs12_file")
+		}
+		keyPEM, chainPEM, err = certs.ParsePKCS12(bundle, passphrase)
+		if err != nil {
+			return Config{}, errors.Wrapf(err, "transport_pkcs12_file (passphrase from %s)", keyPassphraseEnv)
+		}
+		config.TransportKeyPEM, config.TransportCertPEM = string(keyPEM), string(chainPEM)
+	}
+
+	config.SigningKeyPEM, err = decryptPEM(config.SigningKeyPEM, passphrase, "private_key")
+	if err != nil {
+		return Config{}, err
+	}
+	config.TransportKeyPEM, err = decryptPEM(config.TransportKeyPEM, passphrase, "transport_key")
+	if err != nil {
+		return Config{}, err
+	}
+	return config, nil
+}
+
+// pemFromFile is the content of the file at path when set, the inline PEM otherwise
+func pemFromFile(inlinePEM, path, property string) (string, error) {
+	if path == "" {
+		return inlinePEM, nil
+	}
+	if inlinePEM != "" {
+		return "", errors.Errorf("%s and %s_file can't both be set", property, property)
+	}
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", errors.Wrapf(err, "reading %s_file", property)
+	}
+	return string(content), nil
+}
+
+func decryptPEM(keyPEM, passphrase, property string) (string, error) {
+	if keyPEM == "" {
+		return "", nil
+	}
+	decrypted, err := certs.DecryptPrivateKeyPEM([]byte(keyPEM), passphrase)
+	if err != nil {
+		return "", errors.Wrapf(err, "%s (passphrase from %s)", property, keyPassphraseEnv)
+	}
+	return string(decrypted), nil
+}
@@ -4,11 +4,16 @@ import (
 	"bytes"
 	"crypto/rand"
 	"crypto/rsa"
+	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
 	"encoding/pem"
 	"fmt"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/mockaspsp"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/pki"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -103,3 +108,128 @@ func Test_ReadsConfigFromFile_HandlesError(t *testing.T) {
 	_, err := LoadConfig("non_existing_file")
 	require.EqualError(t, err, "load config: open non_existing_file: no such file or directory")
 }
+
+func Test_MockConfig_IsValid(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	config := mockConfig(env, "https://localhost:8443")
+
+	require.NoError(t, validateConfig(config))
+	assert.Equal(t, "https://localhost:8443/.well-known/openid-configuration", config.WellknownEndpoint)
+	assert.Equal(t, env.SoftwareId, config.Issuer)
+	assert.Equal(t, []string{env.CACertPEM}, config.TransportRootCAsPEM)
+}
+
+func Test_MockBaseURL(t *testing.T) {
+	testCases := []struct {
+		addr     string
+		expected string
+	}{
+		{addr: "localhost:8443", expected: "https://localhost:8443"},
+		{addr: ":8443", expected: "https://localhost:8443"},
+		{addr: "0.0.0.0:8443", expected: "https://localhost:8443"},
+		{addr: "127.0.0.1:9443", expected: "https://127.0.0.1:9443"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.addr, func(t *testing.T) {
+			baseURL, err := mockBaseURL(tc.addr)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, baseURL)
+		})
+	}
+}
+
+func Test_PkiOptions(t *testing.T) {
+	opts, err := pkiOptions("software-id", "https://a.com, https://b.com", "AISP,PISP", "tpp.local:8443")
+	require.NoError(t, err)
+
+	assert.Equal(t, "software-id", opts.SoftwareId)
+	assert.Equal(t, []string{"https://a.com", "https://b.com"}, opts.RedirectURIs)
+	assert.Equal(t, []string{"AISP", "PISP"}, opts.Roles)
+	assert.Contains(t, opts.Hosts, "tpp.local")
+	assert.Contains(t, opts.Hosts, "localhost")
+}
+
+func Test_PkiOptions_DefaultsToPKIOptions(t *testing.T) {
+	opts, err := pkiOptions("", "", "", "localhost:8443")
+	require.NoError(t, err)
+
+	assert.Len(t, opts.SoftwareId, 22)
+	assert.Equal(t, pki.Roles, opts.Roles)
+	assert.Equal(t, []string{"localhost", "127.0.0.1", "::1"}, opts.Hosts)
+}
+
+func Test_LoadKeyMaterial_ReadsFiles(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := writeFile(t, dir, "signing.key", "signing key")
+	certPath := writeFile(t, dir, "transport.pem", "transport cert")
+	transportKeyPath := writeFile(t, dir, "transport.key", "transport key")
+	rootCAPath := writeFile(t, dir, "root-ca.pem", "root ca")
+
+	config, err := loadKeyMaterial(Config{
+		SigningKeyFile:        keyPath,
+		TransportCertFile:     certPath,
+		TransportKeyFile:      transportKeyPath,
+		TransportRootCAsPEM:   []string{"inline root ca"},
+		TransportRootCAsFiles: []string{rootCAPath},
+	}, "")
+
+	require.NoError(t, err)
+	assert.Equal(t, "signing key", config.SigningKeyPEM)
+	assert.Equal(t, "transport cert", config.TransportCertPEM)
+	assert.Equal(t, "transport key", config.TransportKeyPEM)
+	assert.Equal(t, []string{"inline root ca", "root ca"}, config.TransportRootCAsPEM)
+}
+
+func Test_LoadKeyMaterial_FailsOnInlineAndFile(t *testing.T) {
+	_, err := loadKeyMaterial(Config{SigningKeyPEM: "key", SigningKeyFile: "signing.key"}, "")
+
+	assert.EqualError(t, err, "private_key and private_key_file can't both be set")
+}
+
+func Test_LoadKeyMaterial_ReadsPKCS12(t *testing.T) {
+	config, err := loadKeyMaterial(Config{TransportPKCS12File: "../../pkg/certs/testdata/transport.p12"}, "changeit")
+
+	require.NoError(t, err)
+	certs, err := tls.X509KeyPair([]byte(config.TransportCertPEM), []byte(config.TransportKeyPEM))
+	require.NoError(t, err)
+	assert.Len(t, certs.Certificate, 2)
+}
+
+func Test_LoadKeyMaterial_DecryptsKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	// nolint:staticcheck
+	block, err := x509.EncryptPEMBlock(
+		rand.Reader,
+		"RSA PRIVATE KEY",
+		x509.MarshalPKCS1PrivateKey(key),
+		[]byte("secret"),
+		x509.PEMCipherAES256,
+	)
+	require.NoError(t, err)
+	encryptedPEM := string(pem.EncodeToMemory(block))
+
+	config, err := loadKeyMaterial(Config{SigningKeyPEM: encryptedPEM, TransportKeyPEM: encryptedPEM}, "secret")
+	require.NoError(t, err)
+	expected := string(pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}))
+	assert.Equal(t, expected, config.SigningKeyPEM)
+	assert.Equal(t, expected, config.TransportKeyPEM)
+
+	_, err = loadKeyMaterial(Config{SigningKeyPEM: encryptedPEM}, "")
+	assert.EqualError(
+		t,
+		err,
+		"private_key (passphrase from DCR_KEY_PASSPHRASE): "+
+			"decrypting private key: the key is encrypted and no passphrase was provided",
+	)
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	path := filepath.Join(dir, name)
+	require.NoError(t, ioutil.WriteFile(path, []byte(content), 0600))
+	return path
+}
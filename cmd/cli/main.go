@@ -2,18 +2,34 @@ package main
 
 import (
 	"bufio"
+	"context"
+	"crypto"
 	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"io/ioutil"
+	"net"
 	http2 "net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 
+	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/ledger"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/mockaspsp"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/pki"
 	ver "github.com/OpenBankingUK/conformance-dcr/pkg/version"
 	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
 )
 
 func main() {
@@ -33,9 +49,28 @@ func main() {
 		versionCmd(vInfo)
 	}
 
-	updateCheckCmd(vInfo)
+	if flags.verifyReportCmd {
+		verifyReportCmd(flags)
+	}
+
+	if flags.mockServerCmd {
+		mockServerCmd(flags)
+	}
+
+	if flags.pkiCmd {
+		pkiCmd(flags)
+	}
+
+	// replayed runs don't need network access
+	if flags.replayPath == "" {
+		updateCheckCmd(vInfo)
+	}
+
+	if flags.cleanupCmd {
+		cleanupCmd(flags)
+	}
 
-	runCmd(flags)
+	runCmd(flags, vInfo)
 }
 
 func versionCmd(v VersionInfo) {
@@ -53,7 +88,7 @@ func updateCheckCmd(v VersionInfo) {
 	}
 }
 
-func runCmd(flags flags) {
+func runCmd(flags flags, vInfo VersionInfo) {
 	if flags.configFilePath == "" {
 		flag.Usage()
 		os.Exit(1)
@@ -62,7 +97,24 @@ func runCmd(flags flags) {
 	cfg, err := LoadConfig(flags.configFilePath)
 	exitOnError(err)
 
-	client := &http2.Client{Timeout: time.Second * 5}
+	var replay *http.ReplayTransport
+	if flags.replayPath != "" {
+		replay, err = newReplayTransport(flags.replayPath)
+		exitOnError(err)
+	}
+
+	var recorder *http.HARRecorder
+	if flags.harPath != "" {
+		recorder = http.NewHARRecorder("conformance-dcr", vInfo.version)
+		if !flags.disableRedaction {
+			recorder = recorder.WithRedactor(http.NewRedactor())
+		}
+	}
+
+	discovery, err := discoveryTransport(cfg.TransportRootCAsPEM)
+	exitOnError(err)
+
+	client := &http2.Client{Timeout: time.Second * 5, Transport: wrapTransport(discovery, replay, recorder)}
 	openIDConfig, err := openid.Get(cfg.WellknownEndpoint, client)
 	exitOnError(err)
 
@@ -84,34 +136,94 @@ func runCmd(flags flags) {
 		flags.tlsSkipVerify,
 		cfg.SpecVersion,
 		cfg.PreferredTokenEndPointAuthMethod,
+		cfg.PreferredSigningAlg,
+		cfg.JwksURI,
 		cfg.CreateSoftwareClientOnly,
 		cfg.AuthorizationSignedResponseAlg,
 	)
 	exitOnError(err)
 
-	manifest, err := compliant.NewSpecManifest(cfg.SpecVersion, dcr32Cfg)
+	// software clients of a replayed run were never registered with the ASPSP
+	if replay == nil {
+		dcr32Cfg.Ledger = ledger.NewLedger(flags.ledgerPath, openIDConfig.RegistrationEndpointAsString())
+	}
+	dcr32Cfg.SecureClient.Transport = wrapTransport(dcr32Cfg.SecureClient.Transport, replay, recorder)
+
+	manifest, err := specOrMatrixManifest(flags.algMatrix, cfg.SpecVersion, dcr32Cfg)
 	exitOnError(err)
 
+	if flags.manifestPath != "" {
+		manifest, err = withManifestFile(manifest, flags.manifestPath, dcr32Cfg)
+		exitOnError(err)
+	}
+
 	if flags.filterExpression != "" {
 		manifest, err = compliant.NewFilteredManifest(manifest, flags.filterExpression)
 		exitOnError(err)
 	}
 
+	if flags.parallel != 1 {
+		manifest, err = compliant.NewParallelManifest(manifest, flags.parallel)
+		exitOnError(err)
+	}
+
+	ctx, cancel := runContext(flags.timeout)
+	if flags.disableRedaction {
+		ctx = compliant.WithoutRedaction(ctx)
+	}
+
 	tester := compliant.NewTester()
 
 	printer := compliant.NewPrinter(flags.debug)
-	tester.AddListener(printer.Print)
+	if flags.parallel > 1 {
+		tester.AddEventListener(printer.PrintFinishedScenario)
+	} else {
+		tester.AddEventListener(printer.PrintEvent)
+	}
+	tester.AddListener(printer.PrintSummary)
+
+	if flags.junitPath != "" {
+		tester.AddListener(junitReport(flags.junitPath))
+	}
+
+	if recorder != nil {
+		tester.AddListener(harReport(flags.harPath, recorder))
+	}
+
+	var signer *compliant.ReportSigner
+	if flags.signReport || flags.signKeyPath != "" {
+		var reportSigner compliant.ReportSigner
+		reportSigner, err = newReportSigner(flags, vInfo, dcr32Cfg.PrivateKey, cfg.Kid)
+		exitOnError(err)
+		signer = &reportSigner
+	}
+
+	if flags.reportDir != "" {
+		dirReporter := compliant.NewDirReporter(runConfig(cfg), flags.debug, flags.reportDir)
+		if signer != nil {
+			dirReporter = dirReporter.WithSigner(*signer)
+		}
+		tester.AddListener(dirReporter.Report)
+	}
 
 	doneSignal := make(chan bool)
 	serverAddr := serverAddress(flags.httpServerPort)
 	if flags.report {
 		reporterFunc := compliant.NewReporter(runConfig(cfg), flags.debug, doneSignal, serverAddr)
+		if signer != nil {
+			reporterFunc = reporterFunc.WithSigner(*signer)
+		}
 		tester.AddListener(reporterFunc.Report)
 	}
 
-	passes, err := tester.Compliant(manifest)
+	passes, err := tester.Compliant(ctx, manifest)
+	cancel()
 	exitOnError(err)
 
+	if flags.reportDir != "" {
+		fmt.Printf("Report written to %s\n", flags.reportDir)
+	}
+
 	if flags.report {
 		waitForDownloadOrTimeout(serverAddr, doneSignal)
 	}
@@ -121,6 +233,381 @@ func runCmd(flags flags) {
 	}
 }
 
+// cleanupCmd deletes software clients recorded in the ledger by previous runs that didn't delete them
+func cleanupCmd(flags flags) {
+	if flags.configFilePath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	cfg, err := LoadConfig(flags.configFilePath)
+	exitOnError(err)
+
+	clientLedger := ledger.NewLedger(flags.ledgerPath, "")
+	entries, err := clientLedger.Entries()
+	exitOnError(err)
+
+	if len(entries) == 0 {
+		fmt.Printf("No software clients to clean up in %s\n", flags.ledgerPath)
+		os.Exit(0)
+	}
+
+	secureClient, err := http.NewBuilder().
+		WithRootCAs(cfg.TransportRootCAsPEM).
+		WithTransportKeyPair(cfg.TransportCertPEM, cfg.TransportKeyPEM).
+		WithTlsSkipVerify(flags.tlsSkipVerify).
+		Build()
+	exitOnError(err)
+
+	manifest, err := compliant.NewCleanupManifest(entries, secureClient, clientLedger)
+	exitOnError(err)
+
+	ctx, cancel := runContext(flags.timeout)
+	if flags.disableRedaction {
+		ctx = compliant.WithoutRedaction(ctx)
+	}
+
+	tester := compliant.NewTester()
+	printer := compliant.NewPrinter(flags.debug)
+	tester.AddEventListener(printer.PrintEvent)
+	tester.AddListener(printer.PrintSummary)
+
+	passes, err := tester.Compliant(ctx, manifest)
+	cancel()
+	exitOnError(err)
+
+	if !passes {
+		os.Exit(1)
+	}
+	os.Exit(0)
+}
+
+// verifyReportCmd checks the signature and file digests of a signed report directory or zip
+func verifyReportCmd(flags flags) {
+	if flags.reportPath == "" || flags.publicKeyPath == "" {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	publicKeyPEM, err := ioutil.ReadFile(flags.publicKeyPath)
+	exitOnError(err)
+	publicKey, err := certs.ParsePublicKeyFromPEM(publicKeyPEM)
+	exitOnError(err)
+
+	claims, err := compliant.VerifyReport(flags.reportPath, publicKey)
+	exitOnError(err)
+
+	fmt.Printf("Report %s verified\n", flags.reportPath)
+	fmt.Printf("  Tool version:  %s (%s)\n", claims.Tool.Version, claims.Tool.CommitHash)
+	fmt.Printf("  Config sha256: %s\n", claims.ConfigSha256)
+	fmt.Printf("  Signed at:     %s\n", time.Unix(claims.IssuedAt, 0).UTC().Format(time.RFC3339))
+	fmt.Printf("  Pass:          %t\n", claims.Pass)
+	os.Exit(0)
+}
+
+// mockServerCmd serves a mock ASPSP with freshly generated keys and certificates, or the PKI in -pki-dir,
+// and writes a config running the tool against it
+func mockServerCmd(flags flags) {
+	env, err := mockEnvironment(flags.pkiDir)
+	exitOnError(err)
+
+	baseURL, err := mockBaseURL(flags.mockAddr)
+	exitOnError(err)
+	faults, err := mockaspsp.ParseFaults(flags.mockFaults)
+	exitOnError(err)
+	serverConfig := env.ServerConfig()
+	serverConfig.Faults = faults
+
+	content, err := json.MarshalIndent(mockConfig(env, baseURL), "", "  ")
+	exitOnError(err)
+	err = ioutil.WriteFile(flags.mockConfigPath, content, 0600)
+	exitOnError(err)
+
+	server := &http2.Server{
+		Addr:      flags.mockAddr,
+		Handler:   mockaspsp.NewServer(serverConfig).Handler(),
+		TLSConfig: env.TLSConfig(),
+	}
+	ctx, cancel := runContext(0)
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	fmt.Printf("Mock ASPSP listening on %s\n", baseURL)
+	if len(faults) > 0 {
+		fmt.Printf("Injecting faults: %s\n", flags.mockFaults)
+	}
+	fmt.Printf("Config written to %s, run the tool with -config-path %s\n", flags.mockConfigPath, flags.mockConfigPath)
+	err = server.ListenAndServeTLS("", "")
+	cancel()
+	if err != http2.ErrServerClosed {
+		exitOnError(err)
+	}
+	os.Exit(0)
+}
+
+// specOrMatrixManifest runs the algorithm matrix with `-alg-matrix`, otherwise the specification scenarios
+func specOrMatrixManifest(algMatrix bool, specVersion string, cfg compliant.DCR32Config) (compliant.Manifest, error) {
+	if algMatrix {
+		return compliant.NewAlgorithmMatrix(cfg)
+	}
+	return compliant.NewSpecManifest(specVersion, cfg)
+}
+
+// withManifestFile runs the scenarios of a manifest file after the specification scenarios
+func withManifestFile(manifest compliant.Manifest, path string, cfg compliant.DCR32Config) (compliant.Manifest, error) {
+	fileManifest, err := compliant.LoadManifestFile(path, cfg)
+	if err != nil {
+		return nil, err
+	}
+	scenarios := compliant.Scenarios{}
+	scenarios = append(scenarios, manifest.Scenarios()...)
+	scenarios = append(scenarios, fileManifest.Scenarios()...)
+	return compliant.NewManifest(manifest.Name(), manifest.Version(), scenarios)
+}
+
+func mockEnvironment(pkiDir string) (mockaspsp.Environment, error) {
+	if pkiDir == "" {
+		return mockaspsp.NewEnvironment()
+	}
+	p, err := pki.Load(pkiDir)
+	if err != nil {
+		return mockaspsp.Environment{}, err
+	}
+	return mockaspsp.EnvironmentFromPKI(p), nil
+}
+
+// pkiCmd generates a local test PKI and software statement, and a config running the tool against a mock-server
+// started with the PKI
+func pkiCmd(flags flags) {
+	opts, err := pkiOptions(flags.softwareId, flags.redirectURIs, flags.softwareRoles, flags.mockAddr)
+	exitOnError(err)
+	p, err := pki.Generate(opts)
+	exitOnError(err)
+	err = p.Write(flags.pkiDir)
+	exitOnError(err)
+
+	baseURL, err := mockBaseURL(flags.mockAddr)
+	exitOnError(err)
+	content, err := json.MarshalIndent(mockConfig(mockaspsp.EnvironmentFromPKI(p), baseURL), "", "  ")
+	exitOnError(err)
+	configPath := filepath.Join(flags.pkiDir, "config.json")
+	err = ioutil.WriteFile(configPath, content, 0600)
+	exitOnError(err)
+
+	fmt.Printf("PKI for software id %s written to %s\n", p.SoftwareId, flags.pkiDir)
+	fmt.Printf("Start the mock with -pki-dir %s mock-server, then run the tool with -config-path %s\n",
+		flags.pkiDir, configPath)
+	os.Exit(0)
+}
+
+// pkiOptions overrides the default PKI options with the comma separated flag values, the server certificate
+// is issued for the mock server address as well
+func pkiOptions(softwareId, redirectURIs, softwareRoles, mockAddr string) (pki.Options, error) {
+	opts := pki.DefaultOptions()
+	if softwareId != "" {
+		opts.SoftwareId = softwareId
+	}
+	if redirectURIs != "" {
+		opts.RedirectURIs = splitList(redirectURIs)
+	}
+	if softwareRoles != "" {
+		opts.Roles = splitList(softwareRoles)
+	}
+
+	host, _, err := net.SplitHostPort(mockAddr)
+	if err != nil {
+		return pki.Options{}, errors.Wrap(err, "mock server address")
+	}
+	if host != "" && host != "0.0.0.0" && !contains(opts.Hosts, host) {
+		opts.Hosts = append(opts.Hosts, host)
+	}
+	return opts, nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// mockConfig runs every scenario against a mock ASPSP served at baseURL
+func mockConfig(env mockaspsp.Environment, baseURL string) Config {
+	return Config{
+		SpecVersion:         "3.3",
+		WellknownEndpoint:   baseURL + "/.well-known/openid-configuration",
+		SSA:                 env.SSA,
+		Kid:                 env.Kid,
+		Aud:                 env.Audience,
+		RedirectURIs:        env.RedirectURIs,
+		Issuer:              env.SoftwareId,
+		SigningKeyPEM:       env.SigningKeyPEM,
+		TransportRootCAsPEM: []string{env.CACertPEM},
+		TransportCertPEM:    env.TransportCertPEM,
+		TransportKeyPEM:     env.TransportKeyPEM,
+		GetImplemented:      true,
+		PutImplemented:      true,
+		DeleteImplemented:   true,
+		Environment:         "mock",
+		Brand:               "Mock ASPSP",
+	}
+}
+
+// mockBaseURL is the URL of the mock server listening on addr, the server certificate is issued for localhost
+func mockBaseURL(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", errors.Wrap(err, "mock server address")
+	}
+	if host == "" || host == "0.0.0.0" {
+		host = "localhost"
+	}
+	return "https://" + net.JoinHostPort(host, port), nil
+}
+
+// discoveryTransport trusts the transport root CAs as well as the system roots, so the well-known endpoint
+// of an ASPSP serving a certificate of its transport PKI, such as the mock server, can be discovered
+func discoveryTransport(transportRootCAs []string) (http2.RoundTripper, error) {
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil {
+		rootCAs = x509.NewCertPool()
+	}
+	certs, err := http.RootCAs(transportRootCAs)
+	if err != nil {
+		return nil, errors.Wrap(err, "discovery transport")
+	}
+	for _, cert := range certs {
+		rootCAs.AddCert(cert)
+	}
+
+	transport := http2.DefaultTransport.(*http2.Transport).Clone()
+	transport.TLSClientConfig = &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	return transport, nil
+}
+
+// newReportSigner signs reports with the key from `-sign-key` when set, otherwise with the config signing key
+func newReportSigner(
+	flags flags,
+	vInfo VersionInfo,
+	signingKey crypto.Signer,
+	kid string,
+) (compliant.ReportSigner, error) {
+	config, err := ioutil.ReadFile(flags.configFilePath)
+	if err != nil {
+		return compliant.ReportSigner{}, errors.Wrap(err, "creating report signer")
+	}
+
+	if flags.signKeyPath != "" {
+		var keyPEM []byte
+		keyPEM, err = ioutil.ReadFile(flags.signKeyPath)
+		if err != nil {
+			return compliant.ReportSigner{}, errors.Wrap(err, "creating report signer")
+		}
+		keyPEM, err = certs.DecryptPrivateKeyPEM(keyPEM, os.Getenv(keyPassphraseEnv))
+		if err != nil {
+			return compliant.ReportSigner{}, errors.Wrap(err, "creating report signer")
+		}
+		signingKey, err = certs.ParsePrivateKeyFromPEM(keyPEM)
+		if err != nil {
+			return compliant.ReportSigner{}, errors.Wrap(err, "creating report signer")
+		}
+		kid = ""
+	}
+
+	tool := compliant.ToolVersion{
+		Version:    vInfo.version,
+		CommitHash: vInfo.commitHash,
+		BuildTime:  vInfo.buildTime,
+	}
+	return compliant.NewReportSigner(signingKey, kid, tool, config), nil
+}
+
+// runContext ends the run on the first interrupt or after `timeout` when set, scenarios in progress still
+// run their teardown and the results are reported as aborted. A second interrupt exits immediately.
+func runContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	return timeoutCtx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// junitReport writes the JUnit XML report to a file once the run completes
+func junitReport(path string) compliant.ListenerFunc {
+	return func(result compliant.ManifestResult) error {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err = compliant.NewJUnitReporter(file).Report(result); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+}
+
+// wrapTransport replaces `next` with the replay transport when replaying and records its traffic when recording
+func wrapTransport(
+	next http2.RoundTripper,
+	replay *http.ReplayTransport,
+	recorder *http.HARRecorder,
+) http2.RoundTripper {
+	if replay != nil {
+		next = replay
+	}
+	if recorder != nil {
+		next = recorder.RoundTripper(next)
+	}
+	return next
+}
+
+func newReplayTransport(path string) (*http.ReplayTransport, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return http.NewReplayTransport(file)
+}
+
+// harReport writes the requests and responses recorded during the run to a HAR file once the run completes
+func harReport(path string, recorder *http.HARRecorder) compliant.ListenerFunc {
+	return func(result compliant.ManifestResult) error {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		if err = recorder.Write(file); err != nil {
+			file.Close()
+			return err
+		}
+		return file.Close()
+	}
+}
+
 func runConfig(config Config) compliant.RunConfig {
 	return compliant.RunConfig{
 		WellknownEndpoint: config.WellknownEndpoint,
@@ -157,21 +644,70 @@ type flags struct {
 	report           bool
 	tlsSkipVerify    bool
 	httpServerPort   string
+	ledgerPath       string
+	cleanupCmd       bool
+	parallel         int
+	timeout          time.Duration
+	junitPath        string
+	reportDir        string
+	signReport       bool
+	signKeyPath      string
+	verifyReportCmd  bool
+	reportPath       string
+	publicKeyPath    string
+	disableRedaction bool
+	harPath          string
+	replayPath       string
+	mockServerCmd    bool
+	mockAddr         string
+	mockConfigPath   string
+	mockFaults       string
+	pkiCmd           bool
+	pkiDir           string
+	softwareId       string
+	redirectURIs     string
+	softwareRoles    string
+	manifestPath     string
+	algMatrix        bool
 }
 
 func mustParseFlags() flags {
-	var configFilePath, filterExpression, httpServerPort string
-	var debug, report, versionFlag, tlsSkipVerify bool
+	var configFilePath, filterExpression, httpServerPort, ledgerPath, junitPath, reportDir string
+	var signKeyPath, publicKeyPath, harPath, replayPath, mockAddr, mockFaults string
+	var pkiDir, softwareId, redirectURIs, softwareRoles, manifestPath string
+	var debug, report, versionFlag, tlsSkipVerify, signReport, disableRedaction, algMatrix bool
+	var parallel int
+	var timeout time.Duration
 	flag.StringVar(&configFilePath, "config-path", "", "Config file path")
 	flag.StringVar(&filterExpression, "filter", "", "Filter scenarios containing value")
 	flag.StringVar(&httpServerPort, "port", "8080", "Http server port for report download")
 	flag.BoolVar(&debug, "debug", false, "Enable debug defaults to disabled")
-	flag.BoolVar(&report, "report", false, "Enable report output defaults to disabled")
+	flag.BoolVar(&disableRedaction, "disable-redaction", false, "Keep credentials in debug output, local use only")
+	flag.BoolVar(&report, "report", false, "Serve the report for download from an embedded webserver")
+	flag.StringVar(&reportDir, "report-dir", "", "Write the report files and zip to this directory")
+	flag.BoolVar(&signReport, "sign-report", false, "Sign the report files with the config signing key")
+	flag.StringVar(&signKeyPath, "sign-key", "", "Sign the report files with the RSA or EC private key in this PEM file")
+	flag.StringVar(&publicKeyPath, "public-key", "", "PEM public key or certificate verifying a signed report")
 	flag.BoolVar(&versionFlag, "version", false, "Print the version details of conformance-dcr")
 	flag.BoolVar(&tlsSkipVerify, "tlsskipverify", false, "Skip ssl cert verify")
+	flag.StringVar(&ledgerPath, "ledger", "dcr-ledger.json", "Ledger file path recording registered software clients")
+	flag.IntVar(&parallel, "parallel", 1, "Number of scenarios to run concurrently")
+	flag.DurationVar(&timeout, "timeout", 0, "Maximum duration of the run, e.g. 10m, defaults to no limit")
+	flag.StringVar(&junitPath, "junit", "", "Write a JUnit XML report to this file path")
+	flag.StringVar(&harPath, "har", "", "Record requests and responses sent to the ASPSP to this HAR file path")
+	flag.StringVar(&replayPath, "replay", "", "Replay responses from this HAR file instead of calling the ASPSP")
+	flag.StringVar(&mockAddr, "mock-addr", "localhost:8443", "Address the mock-server command listens on")
+	flag.StringVar(&mockFaults, "mock-faults", "", "Comma separated faults the mock-server command injects")
+	flag.StringVar(&manifestPath, "manifest", "", "Also run the scenarios of this YAML or JSON manifest file")
+	flag.BoolVar(&algMatrix, "alg-matrix", false, "Register once per advertised auth method and signing alg instead")
+	flag.StringVar(&pkiDir, "pki-dir", "", "PKI directory the mock-server command uses instead of generating one")
+	flag.StringVar(&softwareId, "software-id", "", "software_id of the pki command software statement, random by default")
+	flag.StringVar(&redirectURIs, "redirect-uris", "", "Comma separated software_redirect_uris of the pki command")
+	flag.StringVar(&softwareRoles, "software-roles", "", "Comma separated software_roles of the pki command")
+	flag.Usage = usage
 	flag.Parse()
 
-	return flags{
+	flags := flags{
 		configFilePath:   configFilePath,
 		filterExpression: filterExpression,
 		debug:            debug,
@@ -179,7 +715,68 @@ func mustParseFlags() flags {
 		versionCmd:       versionFlag,
 		tlsSkipVerify:    tlsSkipVerify,
 		httpServerPort:   httpServerPort,
+		ledgerPath:       ledgerPath,
+		cleanupCmd:       flag.Arg(0) == "cleanup",
+		parallel:         parallel,
+		timeout:          timeout,
+		junitPath:        junitPath,
+		reportDir:        reportDir,
+		signReport:       signReport,
+		signKeyPath:      signKeyPath,
+		verifyReportCmd:  flag.Arg(0) == "verify-report",
+		reportPath:       flag.Arg(1),
+		publicKeyPath:    publicKeyPath,
+		disableRedaction: disableRedaction,
+		harPath:          harPath,
+		replayPath:       replayPath,
+		mockServerCmd:    flag.Arg(0) == "mock-server",
+		mockAddr:         mockAddr,
+		mockConfigPath:   mockConfigPath(flag.Arg(1)),
+		mockFaults:       mockFaults,
+		pkiCmd:           flag.Arg(0) == "pki",
+		pkiDir:           pkiDir,
+		softwareId:       softwareId,
+		redirectURIs:     redirectURIs,
+		softwareRoles:    softwareRoles,
+		manifestPath:     manifestPath,
+		algMatrix:        algMatrix,
+	}
+	if flags.pkiCmd {
+		flags.pkiDir = pkiOutputDir(flag.Arg(1))
+	}
+	return flags
+}
+
+// mockConfigPath defaults the config written by the mock-server command to the working directory
+func mockConfigPath(path string) string {
+	if path == "" {
+		return "mock-config.json"
 	}
+	return path
+}
+
+// pkiOutputDir defaults the directory written by the pki command to the working directory
+func pkiOutputDir(path string) string {
+	if path == "" {
+		return "pki"
+	}
+	return path
+}
+
+func usage() {
+	out := flag.CommandLine.Output()
+	fmt.Fprintf(
+		out, "Usage: %s [flags] [cleanup | verify-report REPORT | mock-server [CONFIG] | pki [DIR]]\n\n", os.Args[0],
+	)
+	fmt.Fprintln(out, "Commands:")
+	fmt.Fprintln(out, "  cleanup\tdelete software clients left in the ledger by previous runs")
+	fmt.Fprintln(out, "  verify-report\tcheck the signature of a report directory or zip, requires -public-key")
+	fmt.Fprintln(out, "  mock-server\tserve a mock ASPSP and write a config to run the tool against it to CONFIG,")
+	fmt.Fprintln(out, "             \tmock-config.json by default, listens on -mock-addr")
+	fmt.Fprintln(out, "  pki\t\tgenerate CAs, certificates, a JWKS, a software statement and a config for the")
+	fmt.Fprintln(out, "             \tmock-server to DIR, pki by default")
+	fmt.Fprintln(out, "\nFlags:")
+	flag.PrintDefaults()
 }
 
 func exitOnError(err error) {
@@ -0,0 +1,50 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"github.com/youmark/pkcs8"
+)
+
+// DecryptPrivateKeyPEM decrypts a private key PEM encrypted with a passphrase, either a PBES2 encrypted PKCS#8
+// `ENCRYPTED PRIVATE KEY` as written by `openssl pkey -aes256` or a legacy `Proc-Type: 4,ENCRYPTED` block as written
+// by `openssl rsa -aes256 -traditional`. Keys that aren't encrypted are returned unchanged.
+func DecryptPrivateKeyPEM(keyPEM []byte, passphrase string) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return keyPEM, nil
+	}
+	// nolint:staticcheck
+	encrypted := block.Type == "ENCRYPTED PRIVATE KEY" || x509.IsEncryptedPEMBlock(block)
+	if !encrypted {
+		return keyPEM, nil
+	}
+	if passphrase == "" {
+		return nil, errors.New("decrypting private key: the key is encrypted and no passphrase was provided")
+	}
+	if block.Type == "ENCRYPTED PRIVATE KEY" {
+		return decryptPKCS8(block, passphrase)
+	}
+
+	// legacy PEM encryption has no replacement in the standard library and is only used for these blocks
+	// nolint:staticcheck
+	der, err := x509.DecryptPEMBlock(block, []byte(passphrase))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+}
+
+func decryptPKCS8(block *pem.Block, passphrase string) ([]byte, error) {
+	key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(passphrase))
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting private key")
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "decrypting private key")
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
@@ -0,0 +1,76 @@
+package certs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/youmark/pkcs8"
+)
+
+func TestDecryptPrivateKeyPEM(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	// nolint:staticcheck
+	block, err := x509.EncryptPEMBlock(rand.Reader, "EC PRIVATE KEY", der, []byte("secret"), x509.PEMCipherAES256)
+	require.NoError(t, err)
+	encryptedPEM := pem.EncodeToMemory(block)
+
+	keyPEM, err := DecryptPrivateKeyPEM(encryptedPEM, "secret")
+	require.NoError(t, err)
+	decrypted, err := ParsePrivateKeyFromPEM(keyPEM)
+	require.NoError(t, err)
+	assert.True(t, key.Equal(decrypted))
+
+	_, err = DecryptPrivateKeyPEM(encryptedPEM, "")
+	assert.EqualError(t, err, "decrypting private key: the key is encrypted and no passphrase was provided")
+
+	_, err = DecryptPrivateKeyPEM(encryptedPEM, "wrong")
+	assert.Error(t, err)
+}
+
+func TestDecryptPrivateKeyPEM_ReturnsKeysNotEncrypted(t *testing.T) {
+	keyPEM := pemBlock("EC PRIVATE KEY", []byte("key"))
+
+	decrypted, err := DecryptPrivateKeyPEM(keyPEM, "secret")
+
+	require.NoError(t, err)
+	assert.Equal(t, keyPEM, decrypted)
+}
+
+func TestDecryptPrivateKeyPEM_EncryptedPKCS8(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	assertDecryptsPKCS8(t, rsaKey)
+	assertDecryptsPKCS8(t, ecKey)
+}
+
+func assertDecryptsPKCS8(t *testing.T, key crypto.Signer) {
+	der, err := pkcs8.ConvertPrivateKeyToPKCS8(key, []byte("secret"))
+	require.NoError(t, err)
+	encryptedPEM := pemBlock("ENCRYPTED PRIVATE KEY", der)
+
+	keyPEM, err := DecryptPrivateKeyPEM(encryptedPEM, "secret")
+	require.NoError(t, err)
+	decrypted, err := ParsePrivateKeyFromPEM(keyPEM)
+	require.NoError(t, err)
+	assert.Equal(t, key.Public(), decrypted.Public())
+
+	_, err = DecryptPrivateKeyPEM(encryptedPEM, "")
+	assert.EqualError(t, err, "decrypting private key: the key is encrypted and no passphrase was provided")
+
+	_, err = DecryptPrivateKeyPEM(encryptedPEM, "wrong")
+	assert.Error(t, err)
+}
@@ -1,7 +1,11 @@
 package certs
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/pkg/errors"
 	"io/ioutil"
@@ -25,3 +29,39 @@ func ParseRsaPrivateKeyFromPemFile(privFile string) (*rsa.PrivateKey, error) {
 
 	return privateKey, nil
 }
+
+// ParsePrivateKeyFromPEM parses an RSA or EC private key, the key types the tool can sign requests with
+func ParsePrivateKeyFromPEM(keyPEM []byte) (crypto.Signer, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, errors.New("parsing private key: expected a PEM encoded RSA or EC private key")
+	}
+	if rsaKey, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return rsaKey, nil
+	}
+	if ecKey, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return ecKey, nil
+	}
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		switch k := key.(type) {
+		case *rsa.PrivateKey:
+			return k, nil
+		case *ecdsa.PrivateKey:
+			return k, nil
+		}
+	}
+	return nil, errors.New("parsing private key: expected a PEM encoded RSA or EC private key")
+}
+
+// ParsePublicKeyFromPEM parses an RSA or EC public key, or the public key of a certificate
+func ParsePublicKeyFromPEM(keyPEM []byte) (crypto.PublicKey, error) {
+	rsaKey, err := jwt.ParseRSAPublicKeyFromPEM(keyPEM)
+	if err == nil {
+		return rsaKey, nil
+	}
+	ecKey, err := jwt.ParseECPublicKeyFromPEM(keyPEM)
+	if err == nil {
+		return ecKey, nil
+	}
+	return nil, errors.New("parsing public key: expected a PEM encoded RSA or EC public key or certificate")
+}
@@ -0,0 +1,66 @@
+package certs
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePrivateKeyFromPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	pkcs8DER, err := x509.MarshalPKCS8PrivateKey(ecKey)
+	require.NoError(t, err)
+
+	key, err := ParsePrivateKeyFromPEM(pemBlock("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey)))
+	require.NoError(t, err)
+	assert.Equal(t, rsaKey, key)
+
+	key, err = ParsePrivateKeyFromPEM(pemBlock("EC PRIVATE KEY", ecDER))
+	require.NoError(t, err)
+	assert.True(t, ecKey.Equal(key))
+
+	key, err = ParsePrivateKeyFromPEM(pemBlock("PRIVATE KEY", pkcs8DER))
+	require.NoError(t, err)
+	assert.True(t, ecKey.Equal(key))
+
+	_, err = ParsePrivateKeyFromPEM([]byte("not a key"))
+	assert.EqualError(t, err, "parsing private key: expected a PEM encoded RSA or EC private key")
+}
+
+func TestParsePublicKeyFromPEM(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	rsaDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+	ecDER, err := x509.MarshalPKIXPublicKey(&ecKey.PublicKey)
+	require.NoError(t, err)
+
+	key, err := ParsePublicKeyFromPEM(pemBlock("PUBLIC KEY", rsaDER))
+	require.NoError(t, err)
+	assert.Equal(t, &rsaKey.PublicKey, key)
+
+	key, err = ParsePublicKeyFromPEM(pemBlock("PUBLIC KEY", ecDER))
+	require.NoError(t, err)
+	assert.Equal(t, &ecKey.PublicKey, key)
+
+	_, err = ParsePublicKeyFromPEM([]byte("not a key"))
+	assert.EqualError(t, err, "parsing public key: expected a PEM encoded RSA or EC public key or certificate")
+}
+
+func pemBlock(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
@@ -0,0 +1,52 @@
+package certs
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/x509"
+	"encoding/pem"
+
+	"github.com/pkg/errors"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// ParsePKCS12 converts a PKCS#12 bundle into a PKCS#8 private key PEM and the PEM certificate chain, starting with
+// the certificate of the key. Bundles encrypted with AES, the OpenSSL 3 default, and the legacy algorithms are
+// supported.
+func ParsePKCS12(data []byte, password string) (keyPEM, chainPEM []byte, err error) {
+	privateKey, firstCert, caCerts, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing pkcs12")
+	}
+	key, isSigner := privateKey.(crypto.Signer)
+	if !isSigner {
+		return nil, nil, errors.Errorf("parsing pkcs12: private key type %T is not supported", privateKey)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "parsing pkcs12")
+	}
+
+	// bundles don't always list the certificate of the key first
+	certs := append([]*x509.Certificate{firstCert}, caCerts...)
+	leaf := -1
+	for i, cert := range certs {
+		if publicKey, ok := cert.PublicKey.(interface{ Equal(x crypto.PublicKey) bool }); ok &&
+			publicKey.Equal(key.Public()) {
+			leaf = i
+			break
+		}
+	}
+	if leaf == -1 {
+		return nil, nil, errors.New("parsing pkcs12: no certificate found for the private key")
+	}
+
+	chain := bytes.Buffer{}
+	chain.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certs[leaf].Raw}))
+	for i, cert := range certs {
+		if i != leaf {
+			chain.Write(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert.Raw}))
+		}
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), chain.Bytes(), nil
+}
@@ -0,0 +1,68 @@
+package certs
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePKCS12(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/transport.p12")
+	require.NoError(t, err)
+
+	keyPEM, chainPEM, err := ParsePKCS12(data, "changeit")
+	require.NoError(t, err)
+
+	key, err := ParsePrivateKeyFromPEM(keyPEM)
+	require.NoError(t, err)
+	leaf, rest := pem.Decode(chainPEM)
+	require.NotNil(t, leaf)
+	root, _ := pem.Decode(rest)
+	require.NotNil(t, root)
+	assert.Equal(t, readCertificate(t, "testdata/transport.pem").Raw, leaf.Bytes)
+	assert.Equal(t, readCertificate(t, "testdata/root-ca.pem").Raw, root.Bytes)
+
+	leafCert, err := x509.ParseCertificate(leaf.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, leafCert.PublicKey, key.Public())
+}
+
+func TestParsePKCS12_FailsWithWrongPassword(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/transport.p12")
+	require.NoError(t, err)
+
+	_, _, err = ParsePKCS12(data, "wrong")
+
+	assert.EqualError(t, err, "parsing pkcs12: pkcs12: decryption password incorrect")
+}
+
+func TestParsePKCS12_AESEncryption(t *testing.T) {
+	data, err := ioutil.ReadFile("testdata/transport-aes.p12")
+	require.NoError(t, err)
+
+	keyPEM, chainPEM, err := ParsePKCS12(data, "changeit")
+	require.NoError(t, err)
+
+	key, err := ParsePrivateKeyFromPEM(keyPEM)
+	require.NoError(t, err)
+	leaf, _ := pem.Decode(chainPEM)
+	require.NotNil(t, leaf)
+	assert.Equal(t, readCertificate(t, "testdata/transport.pem").Raw, leaf.Bytes)
+	leafCert, err := x509.ParseCertificate(leaf.Bytes)
+	require.NoError(t, err)
+	assert.Equal(t, leafCert.PublicKey, key.Public())
+}
+
+func readCertificate(t *testing.T, path string) *x509.Certificate {
+	certPEM, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	block, _ := pem.Decode(certPEM)
+	require.NotNil(t, block)
+	cert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+	return cert
+}
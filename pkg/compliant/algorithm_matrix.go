@@ -0,0 +1,106 @@
+package compliant
+
+import (
+	"fmt"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/pkg/errors"
+)
+
+// NewAlgorithmMatrix registers a software client once per token endpoint auth method and signing alg advertised
+// by the well-known, so every combination the ASPSP claims to support is exercised. The request_object_signing_alg
+// claim follows the signing alg when advertised in request_object_signing_alg_values_supported.
+// Request object signing algs are an axis of their own, a software client is registered once per advertised alg
+// with the default token endpoint auth method.
+// Combinations the tool or the signing key can't use are reported as skipped.
+func NewAlgorithmMatrix(cfg DCR32Config) (Manifest, error) {
+	scenarios := Scenarios{}
+	for _, method := range cfg.OpenIDConfig.TokenEndpointAuthMethodsSupported {
+		for _, alg := range matrixSigningAlgs(cfg) {
+			scenarios = append(scenarios, DCR32CreateSoftwareClientWith(cfg, method, alg))
+		}
+	}
+	if len(scenarios) == 0 {
+		return nil, errors.New("no token endpoint auth method and signing alg combination found in the well-known")
+	}
+	for _, alg := range cfg.OpenIDConfig.RequestObjectSignAlgSupported {
+		scenarios = append(scenarios, DCR32CreateSoftwareClientWithRequestObjectSignAlg(cfg, alg))
+	}
+	return NewManifest("DCR algorithm matrix", "1.0", scenarios)
+}
+
+// DCR32CreateSoftwareClientWith is DCR-002 registering with a token endpoint auth method and signing alg
+func DCR32CreateSoftwareClientWith(cfg DCR32Config, authMethod, alg string) Scenario {
+	id := fmt.Sprintf("DCR-002-%s-%s", authMethod, alg)
+	name := fmt.Sprintf("Dynamically create a new software client with %s signed with %s", authMethod, alg)
+	builder := NewBuilder(id, name, specLinkRegisterSoftware)
+
+	if !containsString(auth.TokenEndpointAuthMethods(), authMethod) {
+		return builder.Skip(fmt.Sprintf("token endpoint auth method %s is not supported by the tool", authMethod)).Build()
+	}
+	method := supportedTokenSignMethod(alg)
+	if method == nil {
+		return builder.Skip(fmt.Sprintf("signing alg %s is not supported by the tool", alg)).Build()
+	}
+	if !signMethodMatchesKey(method, cfg.PrivateKey) {
+		return builder.Skip(fmt.Sprintf("signing alg %s does not match the signing key", alg)).Build()
+	}
+
+	authoriserBuilder := cfg.AuthoriserBuilder.
+		WithPreferredTokenEndpointAuthMethod(authMethod).
+		WithTokenEndpointAuthMethod(method).
+		WithRequestObjectSignAlg(matrixRequestObjectSignAlg(cfg, alg))
+
+	return builder.
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, cfg.SecureClient, authoriserBuilder)...).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, cfg.SecureClient)).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, cfg.SecureClient)).
+		Build()
+}
+
+// DCR32CreateSoftwareClientWithRequestObjectSignAlg is DCR-002 registering with a request_object_signing_alg
+func DCR32CreateSoftwareClientWithRequestObjectSignAlg(cfg DCR32Config, alg string) Scenario {
+	id := fmt.Sprintf("DCR-002-request_object-%s", alg)
+	name := fmt.Sprintf("Dynamically create a new software client with request object signing alg %s", alg)
+	builder := NewBuilder(id, name, specLinkRegisterSoftware)
+
+	if alg != "none" {
+		method := supportedTokenSignMethod(alg)
+		if method == nil {
+			return builder.Skip(fmt.Sprintf("request object signing alg %s is not supported by the tool", alg)).Build()
+		}
+		if !signMethodMatchesKey(method, cfg.PrivateKey) {
+			return builder.Skip(fmt.Sprintf("request object signing alg %s does not match the signing key", alg)).Build()
+		}
+	}
+
+	authoriserBuilder := cfg.AuthoriserBuilder.WithRequestObjectSignAlg(alg)
+
+	return builder.
+		TestCase(DCR32CreateSoftwareClientTestCases(cfg, cfg.SecureClient, authoriserBuilder)...).
+		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, cfg.SecureClient)).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, cfg.SecureClient)).
+		Build()
+}
+
+// matrixSigningAlgs are the advertised token endpoint signing algs, or the default alg of the signing key
+// when the well-known doesn't advertise any
+func matrixSigningAlgs(cfg DCR32Config) []string {
+	if cfg.OpenIDConfig.TokenEndpointSigningAlgSupported != nil {
+		return *cfg.OpenIDConfig.TokenEndpointSigningAlgSupported
+	}
+	method, err := defaultTokenSignMethod(cfg.PrivateKey)
+	if err != nil {
+		return nil
+	}
+	return []string{method.Alg()}
+}
+
+// matrixRequestObjectSignAlg is the signing alg when the ASPSP supports it for request objects, otherwise the
+// authoriser default is used
+func matrixRequestObjectSignAlg(cfg DCR32Config, alg string) string {
+	if containsString(cfg.OpenIDConfig.RequestObjectSignAlgSupported, alg) {
+		return alg
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package compliant
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"net/http"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAlgorithmMatrix(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	cfg := DCR32Config{
+		OpenIDConfig: openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"private_key_jwt", "attest_jwt_client_auth"},
+			TokenEndpointSigningAlgSupported:  &[]string{"PS256", "RS256", "ES256"},
+		},
+		PrivateKey:        key,
+		SecureClient:      &http.Client{},
+		AuthoriserBuilder: auth.NewAuthoriserBuilder(),
+	}
+
+	manifest, err := NewAlgorithmMatrix(cfg)
+	require.NoError(t, err)
+
+	expected := []struct {
+		id         string
+		skipReason string
+	}{
+		{id: "DCR-002-private_key_jwt-PS256"},
+		{id: "DCR-002-private_key_jwt-RS256", skipReason: "signing alg RS256 is not supported by the tool"},
+		{id: "DCR-002-private_key_jwt-ES256", skipReason: "signing alg ES256 does not match the signing key"},
+		{
+			id:         "DCR-002-attest_jwt_client_auth-PS256",
+			skipReason: "token endpoint auth method attest_jwt_client_auth is not supported by the tool",
+		},
+		{
+			id:         "DCR-002-attest_jwt_client_auth-RS256",
+			skipReason: "token endpoint auth method attest_jwt_client_auth is not supported by the tool",
+		},
+		{
+			id:         "DCR-002-attest_jwt_client_auth-ES256",
+			skipReason: "token endpoint auth method attest_jwt_client_auth is not supported by the tool",
+		},
+	}
+	require.Len(t, manifest.Scenarios(), len(expected))
+	for i, item := range expected {
+		assert.Equal(t, item.id, manifest.Scenarios()[i].Id())
+		assert.Equal(t, item.skipReason, manifest.Scenarios()[i].(scenario).skipReason)
+	}
+	assert.Equal(
+		t,
+		"Dynamically create a new software client with private_key_jwt signed with PS256",
+		manifest.Scenarios()[0].Name(),
+	)
+}
+
+func TestNewAlgorithmMatrix_RequestObjectSignAlgsAreTheirOwnAxis(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	cfg := DCR32Config{
+		OpenIDConfig: openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"private_key_jwt"},
+			TokenEndpointSigningAlgSupported:  &[]string{"PS256"},
+			RequestObjectSignAlgSupported:     []string{"PS384", "RS256", "ES256", "none"},
+		},
+		PrivateKey:        key,
+		SecureClient:      &http.Client{},
+		AuthoriserBuilder: auth.NewAuthoriserBuilder(),
+	}
+
+	manifest, err := NewAlgorithmMatrix(cfg)
+	require.NoError(t, err)
+
+	expected := []struct {
+		id         string
+		skipReason string
+	}{
+		{id: "DCR-002-private_key_jwt-PS256"},
+		{id: "DCR-002-request_object-PS384"},
+		{id: "DCR-002-request_object-RS256", skipReason: "request object signing alg RS256 is not supported by the tool"},
+		{id: "DCR-002-request_object-ES256", skipReason: "request object signing alg ES256 does not match the signing key"},
+		{id: "DCR-002-request_object-none"},
+	}
+	require.Len(t, manifest.Scenarios(), len(expected))
+	for i, item := range expected {
+		assert.Equal(t, item.id, manifest.Scenarios()[i].Id())
+		assert.Equal(t, item.skipReason, manifest.Scenarios()[i].(scenario).skipReason)
+	}
+	assert.Equal(
+		t,
+		"Dynamically create a new software client with request object signing alg PS384",
+		manifest.Scenarios()[1].Name(),
+	)
+}
+
+func TestNewAlgorithmMatrix_DefaultsToTheSigningKeyAlg(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	cfg := DCR32Config{
+		OpenIDConfig: openid.Configuration{
+			TokenEndpointAuthMethodsSupported: []string{"tls_client_auth"},
+		},
+		PrivateKey:        key,
+		SecureClient:      &http.Client{},
+		AuthoriserBuilder: auth.NewAuthoriserBuilder(),
+	}
+
+	manifest, err := NewAlgorithmMatrix(cfg)
+	require.NoError(t, err)
+
+	require.Len(t, manifest.Scenarios(), 1)
+	assert.Equal(t, "DCR-002-tls_client_auth-ES256", manifest.Scenarios()[0].Id())
+}
+
+func TestNewAlgorithmMatrix_FailsWithoutAuthMethods(t *testing.T) {
+	_, err := NewAlgorithmMatrix(DCR32Config{AuthoriserBuilder: auth.NewAuthoriserBuilder()})
+
+	assert.EqualError(t, err, "no token endpoint auth method and signing alg combination found in the well-known")
+}
+
+func TestMatrixRequestObjectSignAlg(t *testing.T) {
+	cfg := DCR32Config{
+		OpenIDConfig: openid.Configuration{RequestObjectSignAlgSupported: []string{"PS256", "ES256"}},
+	}
+
+	assert.Equal(t, "ES256", matrixRequestObjectSignAlg(cfg, "ES256"))
+	assert.Equal(t, "", matrixRequestObjectSignAlg(cfg, "PS512"))
+}
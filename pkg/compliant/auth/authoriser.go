@@ -1,8 +1,10 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
+	"fmt"
+	"strings"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
@@ -17,166 +19,122 @@ type Authoriser interface {
 	Client(response []byte) (client.Client, error)
 }
 
+// TokenEndpointAuthMethods lists the token endpoint auth methods the tool can register software clients with,
+// in order of preference when the ASPSP supports several
+func TokenEndpointAuthMethods() []string {
+	return []string{
+		"tls_client_auth",
+		"private_key_jwt",
+		"client_secret_jwt",
+		"client_secret_basic",
+		"client_secret_post",
+		"self_signed_tls_client_auth",
+	}
+}
+
 func NewAuthoriser(
 	config openid.Configuration,
 	ssa, aud, kid, issuer string, tokenEndpointSignMethod jwt.SigningMethod,
 	redirectURIs []string,
 	responseTypes []string,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	jwtExpiration time.Duration,
 	transportCert *x509.Certificate,
 	transportSubjectDn string,
 	preferredTokenEndpointAuthMethod string,
 	clientId string,
 	authorizationSignedResponseAlg string,
+	requestObjectSignAlg string,
+	jwksURI string,
 ) Authoriser {
-	requestObjectSignAlg := "none"
-	if len(config.RequestObjectSignAlgSupported) > 0 {
-		requestObjectSignAlg = config.RequestObjectSignAlgSupported[0]
-	}
-
-	if preferredTokenEndpointAuthMethod != "" {
-		if sliceContains(preferredTokenEndpointAuthMethod, config.TokenEndpointAuthMethodsSupported) {
-			if preferredTokenEndpointAuthMethod == "tls_client_auth" {
-				return createNewTlsClientAuth(config, ssa, aud, kid, issuer, tokenEndpointSignMethod, redirectURIs,
-					responseTypes, privateKey, jwtExpiration, transportCert, transportSubjectDn, requestObjectSignAlg,
-					clientId, authorizationSignedResponseAlg)
-			}
-			if preferredTokenEndpointAuthMethod == "private_key_jwt" {
-				return createNewPrivateKeyJwtClient(config, ssa, aud, kid, issuer, tokenEndpointSignMethod, redirectURIs,
-					responseTypes, privateKey, jwtExpiration, transportCert, transportSubjectDn, requestObjectSignAlg,
-					clientId, authorizationSignedResponseAlg)
-			}
+	if requestObjectSignAlg == "" {
+		requestObjectSignAlg = "none"
+		if len(config.RequestObjectSignAlgSupported) > 0 {
+			requestObjectSignAlg = config.RequestObjectSignAlgSupported[0]
 		}
 	}
 
-	if sliceContains("tls_client_auth", config.TokenEndpointAuthMethodsSupported) {
-		return createNewTlsClientAuth(config, ssa, aud, kid, issuer, tokenEndpointSignMethod, redirectURIs, responseTypes,
-			privateKey, jwtExpiration, transportCert, transportSubjectDn, requestObjectSignAlg, clientId, authorizationSignedResponseAlg)
+	method, err := ResolveTokenEndpointAuthMethod(config, preferredTokenEndpointAuthMethod)
+	if err != nil {
+		return none{err: err}
 	}
-	if sliceContains("private_key_jwt", config.TokenEndpointAuthMethodsSupported) {
-		return createNewPrivateKeyJwtClient(config, ssa, aud, kid, issuer, tokenEndpointSignMethod, redirectURIs, responseTypes,
-			privateKey, jwtExpiration, transportCert, transportSubjectDn, requestObjectSignAlg, clientId, authorizationSignedResponseAlg)
-	}
-	if sliceContains("client_secret_jwt", config.TokenEndpointAuthMethodsSupported) {
-		return NewClientSecretJWT(
-			config.TokenEndpoint,
-			NewJwtSigner(
-				tokenEndpointSignMethod,
-				ssa,
-				issuer,
-				aud,
-				kid,
-				"client_secret_jwt",
-				requestObjectSignAlg,
-				redirectURIs,
-				responseTypes,
-				privateKey,
-				jwtExpiration,
-				transportCert,
-				transportSubjectDn,
-				clientId,
-				authorizationSignedResponseAlg,
-			),
-		)
-	}
-	if sliceContains("client_secret_basic", config.TokenEndpointAuthMethodsSupported) {
-		return NewClientSecretBasic(
-			config.TokenEndpoint,
-			NewJwtSigner(
-				tokenEndpointSignMethod,
-				ssa,
-				issuer,
-				aud,
-				kid,
-				"client_secret_basic",
-				requestObjectSignAlg,
-				redirectURIs,
-				responseTypes,
-				privateKey,
-				jwtExpiration,
-				transportCert,
-				transportSubjectDn,
-				clientId,
-				authorizationSignedResponseAlg,
-			),
-		)
+	signer := NewJwtSigner(
+		tokenEndpointSignMethod,
+		ssa,
+		issuer,
+		aud,
+		kid,
+		method,
+		requestObjectSignAlg,
+		redirectURIs,
+		responseTypes,
+		privateKey,
+		jwtExpiration,
+		transportCert,
+		transportSubjectDn,
+		clientId,
+		authorizationSignedResponseAlg,
+		jwksURI,
+	)
+	switch method {
+	case "tls_client_auth":
+		return NewTlsClientAuth(config.TokenEndpoint, signer)
+	case "self_signed_tls_client_auth":
+		return NewSelfSignedTlsClientAuth(config.TokenEndpoint, signer)
+	case "private_key_jwt":
+		return NewClientPrivateKeyJwt(config.TokenEndpoint, tokenEndpointSignMethod, privateKey, signer)
+	case "client_secret_jwt":
+		return NewClientSecretJWT(config.TokenEndpoint, signer)
+	case "client_secret_basic":
+		return NewClientSecretBasic(config.TokenEndpoint, signer)
+	case "client_secret_post":
+		return NewClientSecretPost(config.TokenEndpoint, signer)
 	}
 	return none{}
 }
 
-func createNewPrivateKeyJwtClient(
-	config openid.Configuration,
-	ssa string, aud string, kid string, issuer string,
-	tokenEndpointSignMethod jwt.SigningMethod,
-	redirectURIs []string,
-	responseTypes []string,
-	privateKey *rsa.PrivateKey,
-	jwtExpiration time.Duration,
-	transportCert *x509.Certificate,
-	transportSubjectDn string,
-	requestObjectSignAlg string,
-	clientId string,
-	authorizationSignedResponseAlg string,
-) Authoriser {
-	return NewClientPrivateKeyJwt(
-		config.TokenEndpoint,
-		tokenEndpointSignMethod,
-		privateKey,
-		NewJwtSigner(
-			tokenEndpointSignMethod,
-			ssa,
-			issuer,
-			aud,
-			kid,
-			"private_key_jwt",
-			requestObjectSignAlg,
-			redirectURIs,
-			responseTypes,
-			privateKey,
-			jwtExpiration,
-			transportCert,
-			transportSubjectDn,
-			clientId,
-			authorizationSignedResponseAlg,
-		),
+// TokenEndpointAuthMethodError explains why no token endpoint auth method could be agreed with the ASPSP
+type TokenEndpointAuthMethodError struct {
+	Advertised []string
+	Supported  []string
+	Preferred  string
+}
+
+func (e TokenEndpointAuthMethodError) Error() string {
+	preferred := e.Preferred
+	if preferred == "" {
+		preferred = "not set"
+	}
+	return fmt.Sprintf(
+		"no token endpoint auth method supported by both the ASPSP and the tool: "+
+			"token_endpoint_auth_methods_supported advertises [%s], the tool supports [%s], "+
+			"preferred_token_endpoint_auth_method is %s",
+		strings.Join(e.Advertised, ", "),
+		strings.Join(e.Supported, ", "),
+		preferred,
 	)
 }
 
-func createNewTlsClientAuth(
+// ResolveTokenEndpointAuthMethod is the preferred method when the ASPSP supports it, otherwise the first method
+// supported by both the tool and the ASPSP. Fails with a TokenEndpointAuthMethodError when there is none.
+func ResolveTokenEndpointAuthMethod(
 	config openid.Configuration,
-	ssa string, aud string, kid string, issuer string,
-	tokenEndpointSignMethod jwt.SigningMethod,
-	redirectURIs []string,
-	responseTypes []string,
-	privateKey *rsa.PrivateKey,
-	jwtExpiration time.Duration,
-	transportCert *x509.Certificate,
-	transportSubjectDn string,
-	requestObjectSignAlg string,
-	clientId string,
-	authorizationSignedResponseAlg string,
-) Authoriser {
-	return NewTlsClientAuth(
-		config.TokenEndpoint,
-		NewJwtSigner(
-			tokenEndpointSignMethod,
-			ssa,
-			issuer,
-			aud,
-			kid,
-			"tls_client_auth",
-			requestObjectSignAlg,
-			redirectURIs,
-			responseTypes,
-			privateKey,
-			jwtExpiration,
-			transportCert,
-			transportSubjectDn,
-			clientId,
-			authorizationSignedResponseAlg,
-		),
-	)
+	preferredTokenEndpointAuthMethod string,
+) (string, error) {
+	if sliceContains(preferredTokenEndpointAuthMethod, TokenEndpointAuthMethods()) &&
+		sliceContains(preferredTokenEndpointAuthMethod, config.TokenEndpointAuthMethodsSupported) {
+		return preferredTokenEndpointAuthMethod, nil
+	}
+	for _, method := range TokenEndpointAuthMethods() {
+		if sliceContains(method, config.TokenEndpointAuthMethodsSupported) {
+			return method, nil
+		}
+	}
+	return "", TokenEndpointAuthMethodError{
+		Advertised: config.TokenEndpointAuthMethodsSupported,
+		Supported:  TokenEndpointAuthMethods(),
+		Preferred:  preferredTokenEndpointAuthMethod,
+	}
 }
 
 func sliceContains(value string, list []string) bool {
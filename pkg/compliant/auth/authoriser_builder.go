@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"errors"
 	"time"
@@ -16,13 +16,15 @@ type AuthoriserBuilder struct {
 	tokenEndpointSignMethod          jwt.SigningMethod
 	redirectURIs                     []string
 	responseTypes                    []string
-	privateKey                       *rsa.PrivateKey
+	privateKey                       crypto.Signer
 	jwtExpiration                    time.Duration
 	transportCert                    *x509.Certificate
 	transportCertSubjectDn           string
 	preferredTokenEndpointAuthMethod string
 	clientId                         string
 	authorizationSignedResponseAlg   string
+	requestObjectSignAlg             string
+	jwksURI                          string
 }
 
 func NewAuthoriserBuilder() AuthoriserBuilder {
@@ -81,7 +83,7 @@ func (b AuthoriserBuilder) WithResponseTypes(responseTypes []string) AuthoriserB
 	return b
 }
 
-func (b AuthoriserBuilder) WithPrivateKey(privateKey *rsa.PrivateKey) AuthoriserBuilder {
+func (b AuthoriserBuilder) WithPrivateKey(privateKey crypto.Signer) AuthoriserBuilder {
 	b.privateKey = privateKey
 	return b
 }
@@ -111,6 +113,25 @@ func (b AuthoriserBuilder) WithAuthorizationSignedResponseAlg(authorizationSigne
 	return b
 }
 
+// WithRequestObjectSignAlg sets the request_object_signing_alg claim, the first alg supported by the ASPSP
+// when not set
+func (b AuthoriserBuilder) WithRequestObjectSignAlg(requestObjectSignAlg string) AuthoriserBuilder {
+	b.requestObjectSignAlg = requestObjectSignAlg
+	return b
+}
+
+// WithJwksURI registers self_signed_tls_client_auth clients with a jwks_uri instead of a jwks holding the
+// transport certificate
+func (b AuthoriserBuilder) WithJwksURI(jwksURI string) AuthoriserBuilder {
+	b.jwksURI = jwksURI
+	return b
+}
+
+// TokenEndpointAuthMethod is the token endpoint auth method the authoriser registers software clients with
+func (b AuthoriserBuilder) TokenEndpointAuthMethod() (string, error) {
+	return ResolveTokenEndpointAuthMethod(b.config, b.preferredTokenEndpointAuthMethod)
+}
+
 func (b AuthoriserBuilder) Build() (Authoriser, error) {
 	if b.ssa == "" {
 		return none{}, errors.New("missing ssa from authoriser")
@@ -124,6 +145,9 @@ func (b AuthoriserBuilder) Build() (Authoriser, error) {
 	if b.tokenEndpointSignMethod == nil {
 		return none{}, errors.New("missing token endpoint signing method from authoriser")
 	}
+	if _, err := b.TokenEndpointAuthMethod(); err != nil {
+		return none{err: err}, err
+	}
 	return NewAuthoriser(
 		b.config,
 		b.ssa,
@@ -140,5 +164,7 @@ func (b AuthoriserBuilder) Build() (Authoriser, error) {
 		b.preferredTokenEndpointAuthMethod,
 		b.clientId,
 		b.authorizationSignedResponseAlg,
+		b.requestObjectSignAlg,
+		b.jwksURI,
 	), nil
 }
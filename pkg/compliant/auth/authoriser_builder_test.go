@@ -4,6 +4,7 @@ import (
 	"crypto/rsa"
 	"crypto/x509"
 	"testing"
+	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/dgrijalva/jwt-go"
@@ -31,10 +32,31 @@ func Test_AuthoriserBuilder_FailsOnMissingPrivateKey(t *testing.T) {
 	assert.EqualError(t, err, "missing privateKey from authoriser")
 }
 
+func Test_AuthoriserBuilder_FailsOnNoTokenEndpointAuthMethod(t *testing.T) {
+	authoriser, err := NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"none"}}).
+		WithPreferredTokenEndpointAuthMethod("private_key_jwt").
+		WithSSA("ssa").
+		WithKID("kid").
+		WithPrivateKey(&rsa.PrivateKey{}).
+		WithTokenEndpointAuthMethod(jwt.SigningMethodPS256).
+		Build()
+
+	assert.Equal(t, TokenEndpointAuthMethodError{
+		Advertised: []string{"none"},
+		Supported:  TokenEndpointAuthMethods(),
+		Preferred:  "private_key_jwt",
+	}, err)
+	_, err = authoriser.Claims()
+	assert.IsType(t, TokenEndpointAuthMethodError{}, err)
+}
+
 func Test_AuthoriserBuilder_Success(t *testing.T) {
 	cert := &x509.Certificate{}
+	config := openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"tls_client_auth"}}
 
 	authoriser, err := NewAuthoriserBuilder().
+		WithOpenIDConfig(config).
 		WithSSA("ssa").
 		WithKID("kid").
 		WithIssuer("issuer").
@@ -47,20 +69,22 @@ func Test_AuthoriserBuilder_Success(t *testing.T) {
 
 	assert.NoError(t, err)
 	assert.Equal(t, NewAuthoriser(
-		openid.Configuration{},
+		config,
 		"ssa",
-		"aud",
+		"",
 		"kid",
 		"issuer",
 		jwt.SigningMethodPS256,
 		[]string{"/redirect"},
 		[]string{"code", "code id_token"},
 		&rsa.PrivateKey{},
-		0,
+		time.Hour,
 		cert,
 		"",
 		"",
 		"",
 		"",
+		"",
+		"",
 	), authoriser)
 }
@@ -40,6 +40,8 @@ func TestNewAuther_ReturnsClientSecretBasic(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
+		"",
 	)
 
 	assert.IsType(t, clientSecretBasic{}, auther)
@@ -66,6 +68,8 @@ func TestNewAuther_ReturnsPrivateKeyJwt(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
+		"",
 	)
 
 	assert.IsType(t, clientPrivateKeyJwt{}, auther)
@@ -92,11 +96,69 @@ func TestNewAuther_ReturnsTlsClientAuth(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
+		"",
 	)
 
 	assert.IsType(t, tlsClientAuth{}, auther)
 }
 
+func TestNewAuther_ReturnsClientSecretPost(t *testing.T) {
+	openIdConfig := openid.Configuration{
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_post"},
+	}
+
+	auther := NewAuthoriser(
+		openIdConfig,
+		"ssa",
+		"aud",
+		"kid",
+		"softwareID",
+		jwt.SigningMethodPS256,
+		[]string{},
+		[]string{},
+		&rsa.PrivateKey{},
+		time.Hour,
+		nil,
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+	)
+
+	assert.IsType(t, clientSecretPost{}, auther)
+}
+
+func TestNewAuther_ReturnsSelfSignedTlsClientAuth(t *testing.T) {
+	openIdConfig := openid.Configuration{
+		TokenEndpointAuthMethodsSupported: []string{"self_signed_tls_client_auth"},
+	}
+
+	auther := NewAuthoriser(
+		openIdConfig,
+		"ssa",
+		"aud",
+		"kid",
+		"softwareID",
+		jwt.SigningMethodPS256,
+		[]string{},
+		[]string{},
+		&rsa.PrivateKey{},
+		time.Hour,
+		nil,
+		"",
+		"",
+		"",
+		"",
+		"",
+		"",
+	)
+
+	assert.IsType(t, selfSignedTlsClientAuth{}, auther)
+}
+
 func TestNewAuther_ReturnsNoAuther(t *testing.T) {
 	openIdConfig := openid.Configuration{
 		TokenEndpointAuthMethodsSupported: []string{},
@@ -118,7 +180,96 @@ func TestNewAuther_ReturnsNoAuther(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
+		"",
 	)
 
 	assert.IsType(t, none{}, auther)
+	_, err := auther.Claims()
+	assert.EqualError(
+		t,
+		err,
+		"no token endpoint auth method supported by both the ASPSP and the tool: "+
+			"token_endpoint_auth_methods_supported advertises [], the tool supports [tls_client_auth, "+
+			"private_key_jwt, client_secret_jwt, client_secret_basic, client_secret_post, "+
+			"self_signed_tls_client_auth], preferred_token_endpoint_auth_method is not set",
+	)
+}
+
+func TestResolveTokenEndpointAuthMethod(t *testing.T) {
+	testCases := []struct {
+		name        string
+		advertised  []string
+		preferred   string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:       "first method supported by the tool",
+			advertised: []string{"client_secret_basic", "private_key_jwt"},
+			expected:   "private_key_jwt",
+		},
+		{
+			name:       "preferred method",
+			advertised: []string{"client_secret_basic", "private_key_jwt"},
+			preferred:  "client_secret_basic",
+			expected:   "client_secret_basic",
+		},
+		{
+			name:       "preferred method not advertised",
+			advertised: []string{"client_secret_basic", "private_key_jwt"},
+			preferred:  "tls_client_auth",
+			expected:   "private_key_jwt",
+		},
+		{
+			name:       "no method supported by the tool",
+			advertised: []string{"none", "attest_jwt_client_auth"},
+			preferred:  "tls_client_auth",
+			expectedErr: TokenEndpointAuthMethodError{
+				Advertised: []string{"none", "attest_jwt_client_auth"},
+				Supported:  TokenEndpointAuthMethods(),
+				Preferred:  "tls_client_auth",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			config := openid.Configuration{TokenEndpointAuthMethodsSupported: tc.advertised}
+
+			method, err := ResolveTokenEndpointAuthMethod(config, tc.preferred)
+
+			assert.Equal(t, tc.expectedErr, err)
+			assert.Equal(t, tc.expected, method)
+		})
+	}
+}
+
+func TestNewAuther_ReturnsPreferredTokenEndpointAuthMethod(t *testing.T) {
+	openIdConfig := openid.Configuration{
+		TokenEndpointAuthMethodsSupported: []string{"tls_client_auth", "client_secret_basic"},
+	}
+
+	auther := NewAuthoriser(
+		openIdConfig,
+		"ssa",
+		"aud",
+		"kid",
+		"softwareID",
+		jwt.SigningMethodPS256,
+		[]string{},
+		[]string{},
+		&rsa.PrivateKey{},
+		time.Hour,
+		nil,
+		"",
+		"client_secret_basic",
+		"",
+		"",
+		"",
+		"",
+	)
+
+	assert.IsType(t, clientSecretBasic{}, auther)
 }
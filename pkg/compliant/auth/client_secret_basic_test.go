@@ -33,6 +33,7 @@ func TestNewClientSecretBasicAuther_Claims(t *testing.T) {
 			"",
 			"",
 			"",
+			"",
 		),
 	)
 
@@ -63,6 +64,7 @@ func TestClientSecretBasicAuther_Client_ReturnsAClient(t *testing.T) {
 			"",
 			"",
 			"",
+			"",
 		),
 	)
 
@@ -31,6 +31,7 @@ func TestNewClientSecretJWT_Claims(t *testing.T) {
 			"",
 			"",
 			"",
+			"",
 		),
 	)
 
@@ -61,6 +62,7 @@ func TestClientSecretJWT_Client_ReturnsAClient(t *testing.T) {
 			"",
 			"",
 			"",
+			"",
 		),
 	)
 
@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/pkg/errors"
+)
+
+type clientSecretPost struct {
+	tokenEndpoint string
+	signer        Signer
+}
+
+func NewClientSecretPost(tokenEndpoint string, signer Signer) Authoriser {
+	return clientSecretPost{
+		tokenEndpoint: tokenEndpoint,
+		signer:        signer,
+	}
+}
+
+func (c clientSecretPost) Client(response []byte) (client.Client, error) {
+	var registrationResponse OBClientRegistrationResponse
+	if err := json.NewDecoder(bytes.NewReader(response)).Decode(&registrationResponse); err != nil {
+		return client.NewNoClient(), errors.Wrap(err, "client secret post client")
+	}
+
+	return client.NewClientSecretPost(
+		registrationResponse.ClientID,
+		registrationResponse.RegistrationAccessToken,
+		registrationResponse.ClientSecret,
+		c.tokenEndpoint,
+	), nil
+}
+
+func (c clientSecretPost) Claims() (string, error) {
+	return c.signer.Claims()
+}
@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"io/ioutil"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientSecretPostAuther_Client_ReturnsAClient(t *testing.T) {
+	a := NewClientSecretPost("/token", mockedSigner{})
+
+	data := []byte(`{"client_id": "12345", "registration_access_token": "abcdef", "client_secret": "54321"}`)
+	client, err := a.Client(data)
+	require.NoError(t, err)
+	r, err := client.CredentialsGrantRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "12345", client.Id())
+	assert.Equal(t, "abcdef", client.RegistrationAccessToken())
+
+	body, err := ioutil.ReadAll(r.Body)
+	require.NoError(t, err)
+	values, err := url.ParseQuery(string(body))
+	require.NoError(t, err)
+	assert.Equal(t, "12345", values.Get("client_id"))
+	assert.Equal(t, "54321", values.Get("client_secret"))
+}
+
+func TestClientSecretPostAuther_ClientHandlerMarshalError(t *testing.T) {
+	a := NewClientSecretPost("/token", mockedSigner{})
+
+	_, err := a.Client([]byte(`{`))
+
+	assert.EqualError(t, err, "client secret post client: unexpected EOF")
+}
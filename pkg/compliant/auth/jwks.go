@@ -0,0 +1,54 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha1" // nolint:gosec
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+)
+
+// jwk is a public key in JSON Web Key format with the certificate it belongs to
+type jwk struct {
+	Kty string   `json:"kty"`
+	Use string   `json:"use"`
+	Kid string   `json:"kid"`
+	N   string   `json:"n,omitempty"`
+	E   string   `json:"e,omitempty"`
+	Crv string   `json:"crv,omitempty"`
+	X   string   `json:"x,omitempty"`
+	Y   string   `json:"y,omitempty"`
+	X5c []string `json:"x5c"`
+}
+
+type jwks struct {
+	Keys []jwk `json:"keys"`
+}
+
+// certificateJwks is a key set with the public key of a tls certificate, the kid is the sha1 thumbprint
+// of the certificate public key
+func certificateJwks(cert *x509.Certificate) (jwks, error) {
+	kid := sha1.Sum(cert.RawSubjectPublicKeyInfo) // nolint:gosec
+	key := jwk{
+		Use: "tls",
+		Kid: base64.RawURLEncoding.EncodeToString(kid[:]),
+		X5c: []string{base64.StdEncoding.EncodeToString(cert.Raw)},
+	}
+	switch publicKey := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		key.Kty = "RSA"
+		key.N = base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes())
+		key.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes())
+	case *ecdsa.PublicKey:
+		size := (publicKey.Curve.Params().BitSize + 7) / 8
+		key.Kty = "EC"
+		key.Crv = publicKey.Curve.Params().Name
+		key.X = base64.RawURLEncoding.EncodeToString(publicKey.X.FillBytes(make([]byte, size)))
+		key.Y = base64.RawURLEncoding.EncodeToString(publicKey.Y.FillBytes(make([]byte, size)))
+	default:
+		return jwks{}, fmt.Errorf("unsupported certificate public key type %T", cert.PublicKey)
+	}
+	return jwks{Keys: []jwk{key}}, nil
+}
@@ -5,12 +5,21 @@ import (
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
 )
 
-type none struct{}
+type none struct {
+	err error
+}
 
 func (c none) Claims() (string, error) {
-	return "", errors.New("no authoriser was found for openid config")
+	return "", c.error()
 }
 
 func (c none) Client(response []byte) (client.Client, error) {
-	return client.NewNoClient(), errors.New("no authoriser was found for openid config")
+	return client.NewNoClient(), c.error()
+}
+
+func (c none) error() error {
+	if c.err != nil {
+		return c.err
+	}
+	return errors.New("no authoriser was found for openid config")
 }
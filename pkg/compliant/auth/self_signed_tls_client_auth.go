@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/pkg/errors"
+)
+
+type selfSignedTlsClientAuth struct {
+	tokenEndpoint string
+	signer        Signer
+}
+
+func NewSelfSignedTlsClientAuth(tokenEndpoint string, signer Signer) Authoriser {
+	return selfSignedTlsClientAuth{
+		tokenEndpoint: tokenEndpoint,
+		signer:        signer,
+	}
+}
+
+func (c selfSignedTlsClientAuth) Client(response []byte) (client.Client, error) {
+	var registrationResponse OBClientRegistrationResponse
+	if err := json.NewDecoder(bytes.NewReader(response)).Decode(&registrationResponse); err != nil {
+		return client.NewNoClient(), errors.Wrap(err, "self signed tls client auth")
+	}
+
+	return client.NewSelfSignedTlsClientAuth(
+		registrationResponse.ClientID,
+		registrationResponse.RegistrationAccessToken,
+		c.tokenEndpoint,
+	), nil
+}
+
+func (c selfSignedTlsClientAuth) Claims() (string, error) {
+	return c.signer.Claims()
+}
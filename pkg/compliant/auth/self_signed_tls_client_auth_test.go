@@ -0,0 +1,36 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSelfSignedTlsClientAuth(t *testing.T) {
+	a := NewSelfSignedTlsClientAuth("/token", mockedSigner{})
+
+	data := []byte(`{"client_id": "12345", "registration_access_token": "abcdef"}`)
+	client, err := a.Client(data)
+
+	require.NoError(t, err)
+	assert.Equal(t, "12345", client.Id())
+	assert.Equal(t, "abcdef", client.RegistrationAccessToken())
+}
+
+func TestNewSelfSignedTlsClientAuth_ClientHandlerMarshalError(t *testing.T) {
+	a := NewSelfSignedTlsClientAuth("/token", mockedSigner{})
+
+	_, err := a.Client([]byte(`{`))
+
+	assert.EqualError(t, err, "self signed tls client auth: unexpected EOF")
+}
+
+func TestNewSelfSignedTlsClientAuth_GeneratesClaims(t *testing.T) {
+	a := NewSelfSignedTlsClientAuth("/token", mockedSigner{})
+
+	claims, err := a.Claims()
+
+	require.NoError(t, err)
+	assert.Equal(t, "hello", claims)
+}
@@ -1,7 +1,7 @@
 package auth
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"time"
 
@@ -24,12 +24,13 @@ type jwtSigner struct {
 	requestObjectSignAlg           string
 	redirectURIs                   []string
 	responseTypes                  []string
-	privateKey                     *rsa.PrivateKey
+	privateKey                     crypto.Signer
 	jwtExpiration                  time.Duration
 	transportCert                  *x509.Certificate
 	transportSubjectDn             string
 	clientId                       string
 	authorizationSignedResponseAlg string
+	jwksURI                        string
 }
 
 func NewJwtSigner(
@@ -42,12 +43,13 @@ func NewJwtSigner(
 	requestObjectSignAlg string,
 	redirectURIs []string,
 	responseTypes []string,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	jwtExpiration time.Duration,
 	transportCert *x509.Certificate,
 	transportSubjectDn string,
 	clientId string,
 	authorizationSignedResponseAlg string,
+	jwksURI string,
 ) Signer {
 	return jwtSigner{
 		signingAlgorithm:               signingAlgorithm,
@@ -65,6 +67,7 @@ func NewJwtSigner(
 		transportSubjectDn:             transportSubjectDn,
 		clientId:                       clientId,
 		authorizationSignedResponseAlg: authorizationSignedResponseAlg,
+		jwksURI:                        jwksURI,
 	}
 }
 func (s jwtSigner) Claims() (string, error) {
@@ -128,6 +131,9 @@ func (s jwtSigner) Claims() (string, error) {
 	if err = s.addTlsClientAuthClaims(claims); err != nil {
 		return "", err
 	}
+	if err = s.addSelfSignedTlsClientAuthClaims(claims); err != nil {
+		return "", err
+	}
 
 	s.addSigningAlgClaims(claims)
 
@@ -166,3 +172,26 @@ func (s jwtSigner) addTlsClientAuthClaims(claims jwt.MapClaims) error {
 
 	return nil
 }
+
+// addSelfSignedTlsClientAuthClaims registers the transport certificate the client authenticates with, in a jwks
+// or published at the jwks_uri when set
+func (s jwtSigner) addSelfSignedTlsClientAuthClaims(claims jwt.MapClaims) error {
+	if s.tokenEndpointAuthMethod != "self_signed_tls_client_auth" {
+		return nil
+	}
+
+	if s.jwksURI != "" {
+		claims["jwks_uri"] = s.jwksURI
+		return nil
+	}
+
+	if s.transportCert == nil {
+		return errors.New("transport cert not available")
+	}
+	keys, err := certificateJwks(s.transportCert)
+	if err != nil {
+		return errors.Wrap(err, "self signed tls client auth jwks")
+	}
+	claims["jwks"] = keys
+	return nil
+}
@@ -1,14 +1,19 @@
 package auth
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/rsa"
 	"crypto/x509"
 	"crypto/x509/pkix"
+	"encoding/base64"
 	"fmt"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"math/big"
 	"testing"
 	"time"
 )
@@ -32,6 +37,7 @@ func TestNewJwtSigner(t *testing.T) {
 		"",
 		"",
 		"PS256",
+		"",
 	)
 
 	signedClaims, err := signer.Claims()
@@ -65,6 +71,46 @@ func TestNewJwtSigner(t *testing.T) {
 	assert.Equal(t, "PS256", claims["authorization_signed_response_alg"])
 }
 
+func TestNewJwtSigner_ES256(t *testing.T) {
+	privateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	signer := NewJwtSigner(
+		jwt.SigningMethodES256,
+		"ssa",
+		"issuer",
+		"aud",
+		"kid",
+		"private_key_jwt",
+		"ES256",
+		[]string{"/redirect"},
+		[]string{"code", "code id_token"},
+		privateKey,
+		time.Hour,
+		&x509.Certificate{},
+		"",
+		"",
+		"",
+		"",
+	)
+
+	signedClaims, err := signer.Claims()
+	require.NoError(t, err)
+
+	token, err := jwt.Parse(signedClaims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodECDSA); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return privateKey.Public(), nil
+	})
+	require.NoError(t, err)
+
+	claims, ok := token.Claims.(jwt.MapClaims)
+	assert.True(t, ok)
+	assert.Equal(t, "ES256", token.Header["alg"])
+	assert.Equal(t, "ES256", claims["id_token_signed_response_alg"])
+	assert.Equal(t, "ES256", claims["token_endpoint_auth_signing_alg"])
+}
+
 func TestNewJwtSigner_TlsClientAuthAddSubjectToClaims(t *testing.T) {
 	privateKey, err := certs.ParseRsaPrivateKeyFromPemFile("testdata/private-sign.key")
 	require.NoError(t, err)
@@ -85,6 +131,7 @@ func TestNewJwtSigner_TlsClientAuthAddSubjectToClaims(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
 	)
 
 	token, claims := getJwtClaims(t, signer, privateKey)
@@ -126,6 +173,7 @@ func TestNewJwtSigner_TlsClientAuthAddConfigurableSubjectToClaims(t *testing.T)
 		"CN=Configured Subject DN",
 		"",
 		"",
+		"",
 	)
 
 	_, claims := getJwtClaims(t, signer, privateKey)
@@ -133,6 +181,75 @@ func TestNewJwtSigner_TlsClientAuthAddConfigurableSubjectToClaims(t *testing.T)
 	assert.Equal(t, "CN=Configured Subject DN", claims["tls_client_auth_subject_dn"])
 }
 
+func TestNewJwtSigner_SelfSignedTlsClientAuthAddsJwks(t *testing.T) {
+	privateKey, err := certs.ParseRsaPrivateKeyFromPemFile("testdata/private-sign.key")
+	require.NoError(t, err)
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "self signed"}}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &privateKey.PublicKey, privateKey)
+	require.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	signer := NewJwtSigner(
+		jwt.SigningMethodRS256,
+		"ssa",
+		"issuer",
+		"aud",
+		"kid",
+		"self_signed_tls_client_auth",
+		"none",
+		[]string{"/redirect"},
+		nil,
+		privateKey,
+		time.Hour,
+		cert,
+		"",
+		"",
+		"",
+		"",
+	)
+
+	_, claims := getJwtClaims(t, signer, privateKey)
+
+	keys, ok := claims["jwks"].(map[string]interface{})["keys"].([]interface{})
+	require.True(t, ok)
+	require.Len(t, keys, 1)
+	key := keys[0].(map[string]interface{})
+	assert.Equal(t, "RSA", key["kty"])
+	assert.Equal(t, "tls", key["use"])
+	assert.Equal(t, []interface{}{base64.StdEncoding.EncodeToString(der)}, key["x5c"])
+	assert.NotContains(t, claims, "jwks_uri")
+	assert.NotContains(t, claims, "tls_client_auth_subject_dn")
+}
+
+func TestNewJwtSigner_SelfSignedTlsClientAuthAddsJwksURI(t *testing.T) {
+	privateKey, err := certs.ParseRsaPrivateKeyFromPemFile("testdata/private-sign.key")
+	require.NoError(t, err)
+	signer := NewJwtSigner(
+		jwt.SigningMethodRS256,
+		"ssa",
+		"issuer",
+		"aud",
+		"kid",
+		"self_signed_tls_client_auth",
+		"none",
+		[]string{"/redirect"},
+		nil,
+		privateKey,
+		time.Hour,
+		nil,
+		"",
+		"",
+		"",
+		"https://keystore/software.jwks",
+	)
+
+	_, claims := getJwtClaims(t, signer, privateKey)
+
+	assert.Equal(t, "https://keystore/software.jwks", claims["jwks_uri"])
+	assert.NotContains(t, claims, "jwks")
+}
+
 func getJwtClaims(t *testing.T, signer Signer, privateKey *rsa.PrivateKey) (*jwt.Token, jwt.MapClaims) {
 	signedClaims, err := signer.Claims()
 	require.NoError(t, err)
@@ -169,6 +286,7 @@ func TestNewJwtSigner_TlsClientAuthDoesNotPanicOnMissingCert(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
 	)
 
 	_, err = signer.Claims()
@@ -198,6 +316,7 @@ func TestNewJwtSigner_OmitsEmptyResponseTypes(t *testing.T) {
 		"",
 		"",
 		"",
+		"",
 	)
 	_, claims := getJwtClaims(t, signer, privateKey)
 
@@ -6,22 +6,26 @@ import (
 	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 )
 
 type Builder struct {
-	id   string
-	name string
-	spec string
-	tcs  []TestCase
+	id         string
+	name       string
+	spec       string
+	skipReason string
+	tcs        []TestCase
+	teardown   []TestCase
 }
 
 func NewBuilder(id, name, spec string) *Builder {
 	return &Builder{
-		id:   id,
-		name: name,
-		spec: spec,
-		tcs:  []TestCase{},
+		id:       id,
+		name:     name,
+		spec:     spec,
+		tcs:      []TestCase{},
+		teardown: []TestCase{},
 	}
 }
 
@@ -30,21 +34,40 @@ func (b *Builder) TestCase(tc ...TestCase) *Builder {
 	return b
 }
 
+// Teardown adds test cases that always run after the scenario test cases to clean up,
+// their results are reported separately and don't affect the scenario outcome
+func (b *Builder) Teardown(tc ...TestCase) *Builder {
+	b.teardown = append(b.teardown, tc...)
+	return b
+}
+
+// Skip marks the scenario as skipped, its test cases will not run
+func (b *Builder) Skip(reason string) *Builder {
+	b.skipReason = reason
+	return b
+}
+
 func (b *Builder) Build() Scenario {
-	return NewScenario(b.id, b.name, b.spec, b.tcs)
+	if b.skipReason != "" {
+		return NewSkippedScenario(b.id, b.name, b.spec, b.skipReason)
+	}
+	return NewScenarioWithTeardown(b.id, b.name, b.spec, b.tcs, b.teardown)
 }
 
 type testCaseBuilder struct {
-	name       string
-	steps      []step.Step
-	httpClient *http.Client
+	name         string
+	steps        []step.Step
+	httpClient   *http.Client
+	ledger       step.ClientLedger
+	clientCtxKey string
 }
 
 func NewTestCaseBuilder(name string) *testCaseBuilder {
 	return &testCaseBuilder{
-		name:       name,
-		steps:      []step.Step{},
-		httpClient: newDefaultHttpClient(),
+		name:         name,
+		steps:        []step.Step{},
+		httpClient:   newDefaultHttpClient(),
+		clientCtxKey: clientCtxKey,
 	}
 }
 
@@ -64,6 +87,19 @@ func (t *testCaseBuilder) WithHttpClient(client *http.Client) *testCaseBuilder {
 	return t
 }
 
+// WithClientCtxKey keeps the software client of the steps added next under another context key, so test cases
+// registering several clients in a scenario don't overwrite each other
+func (t *testCaseBuilder) WithClientCtxKey(key string) *testCaseBuilder {
+	t.clientCtxKey = key
+	return t
+}
+
+// WithLedger records registered software clients in a ledger until they are deleted
+func (t *testCaseBuilder) WithLedger(ledger step.ClientLedger) *testCaseBuilder {
+	t.ledger = ledger
+	return t
+}
+
 func (t *testCaseBuilder) Get(url string) *testCaseBuilder {
 	t.steps = append(t.steps, step.NewGetRequest(url, responseCtxKey, t.httpClient))
 	return t
@@ -93,6 +129,13 @@ func (t *testCaseBuilder) AssertStatusCodeBadRequest() *testCaseBuilder {
 	return t
 }
 
+// AssertStatusCode asserts any status code, prefer the named assertions
+func (t *testCaseBuilder) AssertStatusCode(statusCode int) *testCaseBuilder {
+	nextStep := step.NewAssertStatus(statusCode, responseCtxKey)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
 func (t *testCaseBuilder) AssertErrorMessage(errorCode string, errorMessage string) *testCaseBuilder {
 	nextStep := step.NewAssertErrorMessage(errorCode, errorMessage, responseCtxKey)
 	t.steps = append(t.steps, nextStep)
@@ -111,14 +154,26 @@ func (t *testCaseBuilder) AssertContextTypeApplicationHtml() *testCaseBuilder {
 	return t
 }
 
+func (t *testCaseBuilder) AssertContentTypeApplicationJson() *testCaseBuilder {
+	nextStep := step.NewAssertContentType(responseCtxKey, "application/json")
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+func (t *testCaseBuilder) AssertContentType(contentType string) *testCaseBuilder {
+	nextStep := step.NewAssertContentType(responseCtxKey, contentType)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
 func (t *testCaseBuilder) GenerateSignedClaims(authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
-	nextStep := step.NewClaims(jwtClaimsCtxKey, clientCtxKey, authoriserBuilder)
+	nextStep := step.NewClaims(jwtClaimsCtxKey, t.clientCtxKey, authoriserBuilder)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
 
 func (t *testCaseBuilder) GenerateSignedClaimsForRegistrationUpdate(authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
-	nextStep := step.NewClaimsForRegistrationUpdate(jwtClaimsCtxKey, clientCtxKey, authoriserBuilder)
+	nextStep := step.NewClaimsForRegistrationUpdate(jwtClaimsCtxKey, t.clientCtxKey, authoriserBuilder)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
@@ -134,7 +189,7 @@ func (t *testCaseBuilder) ClientUpdate(registrationEndpoint string) *testCaseBui
 		registrationEndpoint,
 		jwtClaimsCtxKey,
 		responseCtxKey,
-		clientCtxKey,
+		t.clientCtxKey,
 		grantTokenCtxKey,
 		t.httpClient,
 	)
@@ -143,19 +198,42 @@ func (t *testCaseBuilder) ClientUpdate(registrationEndpoint string) *testCaseBui
 }
 
 func (t *testCaseBuilder) ClientDelete(registrationEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientDelete(registrationEndpoint, clientCtxKey, grantTokenCtxKey, t.httpClient)
-	t.steps = append(t.steps, nextStep)
+	nextStep := step.NewClientDelete(registrationEndpoint, t.clientCtxKey, grantTokenCtxKey, t.httpClient)
+	t.steps = append(t.steps, t.forgetClient(nextStep))
+	return t
+}
+
+func (t *testCaseBuilder) ClientTeardown(registrationEndpoint string) *testCaseBuilder {
+	nextStep := step.NewClientTeardown(registrationEndpoint, t.clientCtxKey, t.httpClient)
+	t.steps = append(t.steps, t.forgetClient(nextStep))
 	return t
 }
 
 func (t *testCaseBuilder) ClientRetrieve(registrationEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientRetrieve(responseCtxKey, registrationEndpoint, clientCtxKey, "", t.httpClient)
+	nextStep := step.NewClientRetrieve(responseCtxKey, registrationEndpoint, t.clientCtxKey, "", t.httpClient)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
 
 func (t *testCaseBuilder) ClientRetrieveInvalidRegistrationAccessToken(registrationEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientRetrieve(responseCtxKey, registrationEndpoint, clientCtxKey, "invalid-access-token-123", t.httpClient)
+	nextStep := step.NewClientRetrieve(
+		responseCtxKey,
+		registrationEndpoint,
+		t.clientCtxKey,
+		"invalid-access-token-123",
+		t.httpClient,
+	)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
+// ClientRetrieveWithRegistrationAccessToken retrieves the software client in context with another token
+func (t *testCaseBuilder) ClientRetrieveWithRegistrationAccessToken(
+	registrationEndpoint, registrationAccessToken string,
+) *testCaseBuilder {
+	nextStep := step.NewClientRetrieve(
+		responseCtxKey, registrationEndpoint, t.clientCtxKey, registrationAccessToken, t.httpClient,
+	)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
@@ -166,14 +244,22 @@ func (t *testCaseBuilder) AssertValidSchemaResponse(validator schema.Validator)
 	return t
 }
 
+// CaptureRegisteredClient keeps the software client of a register response in context and ledger before the
+// response is asserted, so it is deleted even when the ASPSP answered with an unexpected status or content type
+func (t *testCaseBuilder) CaptureRegisteredClient(authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
+	nextStep := step.NewClientRegisterCapture(responseCtxKey, t.clientCtxKey, authoriserBuilder)
+	t.steps = append(t.steps, t.recordClient(nextStep))
+	return t
+}
+
 func (t *testCaseBuilder) ParseClientRegisterResponse(authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
-	nextStep := step.NewClientRegisterResponse(responseCtxKey, clientCtxKey, authoriserBuilder)
-	t.steps = append(t.steps, nextStep)
+	nextStep := step.NewClientRegisterResponse(responseCtxKey, t.clientCtxKey, authoriserBuilder)
+	t.steps = append(t.steps, t.recordClient(nextStep))
 	return t
 }
 
 func (t *testCaseBuilder) ParseClientRetrieveResponse(openIDConfigTokenEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientRetrieveResponse(responseCtxKey, clientCtxKey, openIDConfigTokenEndpoint)
+	nextStep := step.NewClientRetrieveResponse(responseCtxKey, t.clientCtxKey, openIDConfigTokenEndpoint)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
@@ -184,17 +270,43 @@ func (t *testCaseBuilder) ValidateRegistrationEndpoint(registrationEndpoint *str
 	return t
 }
 
+func (t *testCaseBuilder) ValidateTokenEndpointAuthMethod(authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
+	nextStep := step.NewValidateTokenEndpointAuthMethod(authoriserBuilder)
+	t.steps = append(t.steps, nextStep)
+	return t
+}
+
 func (t *testCaseBuilder) GetClientCredentialsGrant(tokenEndpoint string) *testCaseBuilder {
-	nextStep := step.NewClientCredentialsGrant(grantTokenCtxKey, clientCtxKey, tokenEndpoint, t.httpClient)
+	nextStep := step.NewClientCredentialsGrant(grantTokenCtxKey, t.clientCtxKey, tokenEndpoint, t.httpClient)
 	t.steps = append(t.steps, nextStep)
 	return t
 }
 
+// SetClient puts an already registered software client in context
+func (t *testCaseBuilder) SetClient(client dcr.Client) *testCaseBuilder {
+	t.steps = append(t.steps, step.NewSetClient(t.clientCtxKey, client))
+	return t
+}
+
 func (t *testCaseBuilder) Step(nextStep step.Step) *testCaseBuilder {
 	t.steps = append(t.steps, nextStep)
 	return t
 }
 
+func (t *testCaseBuilder) recordClient(nextStep step.Step) step.Step {
+	if t.ledger == nil {
+		return nextStep
+	}
+	return step.NewRecordClient(nextStep, t.clientCtxKey, t.ledger)
+}
+
+func (t *testCaseBuilder) forgetClient(nextStep step.Step) step.Step {
+	if t.ledger == nil {
+		return nextStep
+	}
+	return step.NewForgetClient(nextStep, t.clientCtxKey, t.ledger)
+}
+
 func (t *testCaseBuilder) Build() TestCase {
 	return NewTestCase(t.name, t.steps)
 }
@@ -1,12 +1,20 @@
 package compliant
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
+	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/ledger"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/require"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
@@ -46,6 +54,7 @@ func TestNewTestCaseBuilder(t *testing.T) {
 		AssertStatusCodeBadRequest().
 		AssertStatusCodeCreated().
 		AssertContextTypeApplicationHtml().
+		AssertContentTypeApplicationJson().
 		GenerateSignedClaims(authoriserBuilder).
 		PostClientRegister(sampleEndpoint).
 		ParseClientRegisterResponse(authoriserBuilder).
@@ -57,5 +66,44 @@ func TestNewTestCaseBuilder(t *testing.T) {
 		GetClientCredentialsGrant(sampleEndpoint)
 
 	assert.Equal(t, "test case", tc.name)
-	assert.Len(t, tc.steps, 15)
+	assert.Len(t, tc.steps, 16)
+}
+
+func TestTestCaseBuilder_CaptureRegisteredClient_RecordsClientWhenAssertionsFail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"client_id": "12345"}`))
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "ledger")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	clientLedger := ledger.NewLedger(filepath.Join(dir, "ledger.json"), server.URL)
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	authoriserBuilder := auth.NewAuthoriserBuilder().
+		WithIssuer("issuer").
+		WithKID("kid").
+		WithSSA("ssa").
+		WithPrivateKey(privateKey).
+		WithTokenEndpointAuthMethod(jwt.SigningMethodPS256).
+		WithOpenIDConfig(openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"client_secret_basic"}}).
+		WithJwtExpiration(time.Hour)
+
+	result := NewTestCaseBuilder("register").
+		WithHttpClient(server.Client()).
+		WithLedger(clientLedger).
+		Get(server.URL).
+		CaptureRegisteredClient(authoriserBuilder).
+		AssertStatusCodeCreated().
+		Build().
+		Run(step.NewContext())
+
+	assert.Equal(t, step.StatusFail, result.Status())
+	entries, err := clientLedger.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "12345", entries[0].ClientId)
 }
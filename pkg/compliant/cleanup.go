@@ -0,0 +1,47 @@
+package compliant
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/ledger"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+)
+
+// NewCleanupManifest deletes software clients left in a ledger by runs that didn't finish,
+// clients already deleted on the ASPSP are skipped and removed from the ledger
+func NewCleanupManifest(
+	entries []ledger.Entry,
+	secureClient *http.Client,
+	clientLedger step.ClientLedger,
+) (Manifest, error) {
+	scenarios := Scenarios{}
+	for key, entry := range entries {
+		scenarios = append(scenarios, cleanupScenario(key+1, entry, secureClient, clientLedger))
+	}
+	return NewManifest("Cleanup", "1.0", scenarios)
+}
+
+func cleanupScenario(
+	number int,
+	entry ledger.Entry,
+	secureClient *http.Client,
+	clientLedger step.ClientLedger,
+) Scenario {
+	return NewBuilder(
+		fmt.Sprintf("CLEANUP-%03d", number),
+		fmt.Sprintf("Delete software client %s registered at %s", entry.ClientId, entry.CreatedAt.Format(time.RFC3339)),
+		specLinkDeleteSoftware,
+	).
+		TestCase(
+			NewTestCaseBuilder("Delete leftover software client").
+				WithHttpClient(secureClient).
+				WithLedger(clientLedger).
+				SetClient(client.NewRegisteredClient(entry.ClientId, entry.RegistrationAccessToken)).
+				ClientTeardown(entry.RegistrationEndpoint).
+				Build(),
+		).
+		Build()
+}
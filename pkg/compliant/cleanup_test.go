@@ -0,0 +1,53 @@
+package compliant
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/ledger"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCleanupManifest_DeletesLeftoverClients(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		if r.URL.Path == "/deleted" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	dir, err := ioutil.TempDir("", "cleanup")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	clientLedger := ledger.NewLedger(filepath.Join(dir, "ledger.json"), server.URL)
+	require.NoError(t, clientLedger.Add(client.NewRegisteredClient("leftover", "token")))
+	require.NoError(t, clientLedger.Add(client.NewRegisteredClient("deleted", "token")))
+	entries, err := clientLedger.Entries()
+	require.NoError(t, err)
+
+	manifest, err := NewCleanupManifest(entries, server.Client(), clientLedger)
+	require.NoError(t, err)
+	result := manifest.Run(context.Background())
+
+	require.Len(t, result.Results, 2)
+	assert.Equal(t, "CLEANUP-001", result.Results[0].Id)
+	assert.Equal(t, step.StatusPass, result.Results[0].Status())
+	assert.Equal(t, "CLEANUP-002", result.Results[1].Id)
+	deleteResult := result.Results[1].TestCaseResults[0].Results[1]
+	assert.Equal(t, step.StatusSkip, deleteResult.Status())
+	assert.Equal(t, "software client already deleted, status code 404", deleteResult.SkipReason)
+	remaining, err := clientLedger.Entries()
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type clientSecretPost struct {
+	id                      string
+	registrationAccessToken string
+	secret                  string
+	tokenEndpoint           string
+}
+
+func NewClientSecretPost(id, registrationAccessToken, secret, tokenEndpoint string) Client {
+	return clientSecretPost{
+		id:                      id,
+		registrationAccessToken: registrationAccessToken,
+		secret:                  secret,
+		tokenEndpoint:           tokenEndpoint,
+	}
+}
+
+func (c clientSecretPost) Id() string {
+	return c.id
+}
+
+func (c clientSecretPost) RegistrationAccessToken() string {
+	return c.registrationAccessToken
+}
+
+// CredentialsGrantRequest sends the client credentials in the request body
+func (c clientSecretPost) CredentialsGrantRequest() (*http.Request, error) {
+	data := url.Values{}
+	data.Set("grant_type", "client_credentials")
+	data.Set("scope", "openid")
+	data.Set("client_id", c.id)
+	data.Set("client_secret", c.secret)
+	reqBody := strings.NewReader(data.Encode())
+	r, err := http.NewRequest(http.MethodPost, c.tokenEndpoint, reqBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error making token request for client_secret_post: %s", err.Error())
+	}
+	return r, nil
+}
@@ -0,0 +1,28 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/url"
+	"testing"
+)
+
+func TestClientSecretPost(t *testing.T) {
+	client := NewClientSecretPost("id", "regAccessToken", "secret", "http://endpoint")
+
+	request, err := client.CredentialsGrantRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "id", client.Id())
+	assert.Equal(t, "regAccessToken", client.RegistrationAccessToken())
+	assert.Empty(t, request.Header.Get("Authorization"))
+
+	bodyByes, err := ioutil.ReadAll(request.Body)
+	require.NoError(t, err)
+	bodyDecoded, err := url.ParseQuery(string(bodyByes))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"client_credentials"}, bodyDecoded["grant_type"])
+	assert.Equal(t, []string{"id"}, bodyDecoded["client_id"])
+	assert.Equal(t, []string{"secret"}, bodyDecoded["client_secret"])
+}
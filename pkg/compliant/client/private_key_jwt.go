@@ -1,7 +1,7 @@
 package client
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/google/uuid"
 	"github.com/pkg/errors"
@@ -15,13 +15,13 @@ type privateKeyJwt struct {
 	id                      string
 	registrationAccessToken string
 	tokenEndpoint           string
-	privateKey              *rsa.PrivateKey
+	privateKey              crypto.Signer
 	signingAlgorithm        jwt.SigningMethod
 }
 
 func NewPrivateKeyJwt(
 	id, registrationAccessToken, tokenEndpoint string,
-	privateKey *rsa.PrivateKey,
+	privateKey crypto.Signer,
 	signingAlgorithm jwt.SigningMethod,
 ) Client {
 	return privateKeyJwt{
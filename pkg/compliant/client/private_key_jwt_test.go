@@ -1,6 +1,8 @@
 package client
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/rsa"
 	"github.com/dgrijalva/jwt-go"
@@ -31,3 +33,23 @@ func TestPrivateKeyJwt(t *testing.T) {
 	require.Equal(t, 1, len(bodyDecoded["grant_type"]))
 	require.Equal(t, "client_credentials", bodyDecoded["grant_type"][0])
 }
+
+func TestPrivateKeyJwt_ES256(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	client := NewPrivateKeyJwt("id", "regAccessToken", "token", key, jwt.SigningMethodES256)
+
+	request, err := client.CredentialsGrantRequest()
+	require.NoError(t, err)
+	bodyByes, err := ioutil.ReadAll(request.Body)
+	require.NoError(t, err)
+	bodyDecoded, err := url.ParseQuery(string(bodyByes))
+	require.NoError(t, err)
+
+	require.Equal(t, 1, len(bodyDecoded["client_assertion"]))
+	token, err := jwt.Parse(bodyDecoded["client_assertion"][0], func(token *jwt.Token) (interface{}, error) {
+		return &key.PublicKey, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "ES256", token.Method.Alg())
+}
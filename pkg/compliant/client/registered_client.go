@@ -0,0 +1,33 @@
+package client
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+)
+
+// registeredClient is a software client known only by its registration details, such as one restored
+// from a ledger, it can be managed on the registration endpoint but can't request tokens
+type registeredClient struct {
+	id                      string
+	registrationAccessToken string
+}
+
+func NewRegisteredClient(id, registrationAccessToken string) Client {
+	return registeredClient{
+		id:                      id,
+		registrationAccessToken: registrationAccessToken,
+	}
+}
+
+func (c registeredClient) Id() string {
+	return c.id
+}
+
+func (c registeredClient) RegistrationAccessToken() string {
+	return c.registrationAccessToken
+}
+
+func (c registeredClient) CredentialsGrantRequest() (*http.Request, error) {
+	return nil, errors.New("credentials grant not supported for a registered client")
+}
@@ -0,0 +1,17 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewRegisteredClient(t *testing.T) {
+	client := NewRegisteredClient("id", "token")
+
+	_, err := client.CredentialsGrantRequest()
+
+	assert.EqualError(t, err, "credentials grant not supported for a registered client")
+	assert.Equal(t, "id", client.Id())
+	assert.Equal(t, "token", client.RegistrationAccessToken())
+}
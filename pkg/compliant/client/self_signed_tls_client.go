@@ -0,0 +1,45 @@
+package client
+
+import (
+	"github.com/pkg/errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type selfSignedTlsClient struct {
+	id                      string
+	registrationAccessToken string
+	tokenEndpoint           string
+}
+
+// NewSelfSignedTlsClientAuth authenticates with the transport certificate registered in the client jwks,
+// the token request is the same as tls_client_auth
+func NewSelfSignedTlsClientAuth(id, registrationAccessToken, tokenEndpoint string) Client {
+	return selfSignedTlsClient{
+		id:                      id,
+		registrationAccessToken: registrationAccessToken,
+		tokenEndpoint:           tokenEndpoint,
+	}
+}
+
+func (c selfSignedTlsClient) Id() string {
+	return c.id
+}
+
+func (c selfSignedTlsClient) RegistrationAccessToken() string {
+	return c.registrationAccessToken
+}
+
+func (c selfSignedTlsClient) CredentialsGrantRequest() (*http.Request, error) {
+	data := url.Values{}
+	data.Set("client_id", c.id)
+	data.Set("scope", "openid")
+	data.Set("grant_type", "client_credentials")
+	reqBody := strings.NewReader(data.Encode())
+	r, err := http.NewRequest(http.MethodPost, c.tokenEndpoint, reqBody)
+	if err != nil {
+		return nil, errors.Wrapf(err, "error making token request for self_signed_tls_client_auth: %s", err.Error())
+	}
+	return r, nil
+}
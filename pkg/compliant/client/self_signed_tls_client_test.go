@@ -0,0 +1,26 @@
+package client
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/url"
+	"testing"
+)
+
+func TestClientSelfSignedTlsClientAuth(t *testing.T) {
+	client := NewSelfSignedTlsClientAuth("id", "regAccessToken", "token")
+
+	request, err := client.CredentialsGrantRequest()
+	require.NoError(t, err)
+	assert.Equal(t, "id", client.Id())
+	assert.Equal(t, "regAccessToken", client.RegistrationAccessToken())
+	bodyByes, err := ioutil.ReadAll(request.Body)
+	require.NoError(t, err)
+	bodyDecoded, err := url.ParseQuery(string(bodyByes))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"id"}, bodyDecoded["client_id"])
+	assert.Equal(t, []string{"client_credentials"}, bodyDecoded["grant_type"])
+	assert.Empty(t, bodyDecoded["client_secret"])
+}
@@ -1,10 +1,11 @@
 package compliant
 
 import (
+	"crypto"
+	"crypto/ecdsa"
 	"crypto/rand"
 	"crypto/rsa"
 	"fmt"
-	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/dgrijalva/jwt-go"
 	"net/http"
 	"time"
@@ -22,6 +23,12 @@ const (
 	specLinkUpdateSoftware   = "https://openbanking.atlassian.net/wiki/spaces/DZ/pages/1078034771/Dynamic+Client+Registration+-+v3.2#DynamicClientRegistration-v3.2-PUT/register/{ClientId}"
 )
 
+const (
+	skipReasonGetNotImplemented    = "GET endpoint not implemented"
+	skipReasonPutNotImplemented    = "PUT endpoint not implemented"
+	skipReasonDeleteNotImplemented = "DELETE endpoint not implemented"
+)
+
 func NewDCR32(cfg DCR32Config) (Manifest, error) {
 	secureClient := cfg.SecureClient
 	authoriserBuilder := cfg.AuthoriserBuilder
@@ -35,11 +42,16 @@ func NewDCR32(cfg DCR32Config) (Manifest, error) {
 	if err != nil {
 		return nil, err
 	}
+	invalidRegistrationRequestScenario, err := DCR32CreateInvalidRegistrationRequest(cfg, secureClient, authoriserBuilder)
+	if err != nil {
+		return nil, err
+	}
 	scenarios := Scenarios{
 		DCR32ValidateOIDCConfigRegistrationURL(cfg),
+		DCR32ValidateTokenEndpointAuthMethod(authoriserBuilder),
 		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder),
 		DCR32DeleteSoftwareClient(cfg, secureClient, authoriserBuilder),
-		DCR32CreateInvalidRegistrationRequest(cfg, secureClient, authoriserBuilder),
+		invalidRegistrationRequestScenario,
 		DCR32RetrieveSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32RetrieveWithInvalidCredentials(cfg, secureClient, authoriserBuilder),
 		DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder),
@@ -57,6 +69,7 @@ func NewDCR32CreateSoftwareClientOnly(cfg DCR32Config) (Manifest, error) {
 	secureClient := cfg.SecureClient
 	authoriserBuilder := cfg.AuthoriserBuilder
 	scenarios := Scenarios{
+		DCR32ValidateTokenEndpointAuthMethod(authoriserBuilder),
 		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder),
 	}
 
@@ -75,6 +88,20 @@ func DCR32ValidateOIDCConfigRegistrationURL(cfg DCR32Config) Scenario {
 	).Build()
 }
 
+// DCR32ValidateTokenEndpointAuthMethod fails with a diagnostic when the well-known doesn't advertise any token
+// endpoint auth method the tool supports, instead of registration failing further down the line
+func DCR32ValidateTokenEndpointAuthMethod(authoriserBuilder auth.AuthoriserBuilder) Scenario {
+	return NewBuilder(
+		"DCR-014",
+		"Validate OIDC Config Token Endpoint Auth Methods",
+		specLinkDiscovery,
+	).TestCase(
+		NewTestCaseBuilder("Validate Token Endpoint Auth Method").
+			ValidateTokenEndpointAuthMethod(authoriserBuilder).
+			Build(),
+	).Build()
+}
+
 func DCR32CreateSoftwareClient(
 	cfg DCR32Config,
 	secureClient *http.Client,
@@ -87,6 +114,7 @@ func DCR32CreateSoftwareClient(
 	).
 		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
 		Build()
 }
 
@@ -98,10 +126,13 @@ func DCR32CreateSoftwareClientTestCases(
 	return []TestCase{
 		NewTestCaseBuilder("Register software client").
 			WithHttpClient(secureClient).
+			WithLedger(cfg.Ledger).
 			GenerateSignedClaims(authoriserBuilder).
 			PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 			OutputTransactionId().
+			CaptureRegisteredClient(authoriserBuilder).
 			AssertStatusCodeCreated().
+			AssertContentTypeApplicationJson().
 			ParseClientRegisterResponse(authoriserBuilder).
 			Build(),
 		NewTestCaseBuilder("Retrieve client credentials grant").
@@ -117,17 +148,77 @@ func DCR32DeleteSoftwareClientTestCase(
 ) TestCase {
 	name := "Delete software client"
 	if !cfg.DeleteImplemented {
-		return NewTestCase(
-			fmt.Sprintf("(SKIP Delete endpoint not implemented) %s", name),
-			[]step.Step{},
-		)
+		return NewSkippedTestCase(name, skipReasonDeleteNotImplemented)
 	}
 	return NewTestCaseBuilder(name).
 		WithHttpClient(secureClient).
+		WithLedger(cfg.Ledger).
 		ClientDelete(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 		Build()
 }
 
+// DCR32TeardownSoftwareClientTestCase deletes the software client registered by a scenario,
+// it's used as a scenario teardown so clients are not left behind when a scenario fails
+func DCR32TeardownSoftwareClientTestCase(
+	cfg DCR32Config,
+	secureClient *http.Client,
+) TestCase {
+	return teardownSoftwareClientTestCase(cfg, secureClient, "Teardown software client", clientCtxKey)
+}
+
+func teardownSoftwareClientTestCase(cfg DCR32Config, secureClient *http.Client, name, key string) TestCase {
+	if !cfg.DeleteImplemented {
+		return NewSkippedTestCase(name, skipReasonDeleteNotImplemented)
+	}
+	return NewTestCaseBuilder(name).
+		WithClientCtxKey(key).
+		WithHttpClient(secureClient).
+		WithLedger(cfg.Ledger).
+		ClientTeardown(cfg.OpenIDConfig.RegistrationEndpointAsString()).
+		Build()
+}
+
+// invalidRegistrations builds test cases registering with requests the ASPSP must reject, a software client
+// registered anyway is kept under a context key of its own so the scenario teardown deletes each of them
+type invalidRegistrations struct {
+	cfg          DCR32Config
+	secureClient *http.Client
+	names        []string
+}
+
+func newInvalidRegistrations(cfg DCR32Config, secureClient *http.Client) *invalidRegistrations {
+	return &invalidRegistrations{cfg: cfg, secureClient: secureClient}
+}
+
+func (r *invalidRegistrations) register(name string, authoriserBuilder auth.AuthoriserBuilder) *testCaseBuilder {
+	r.names = append(r.names, name)
+	return NewTestCaseBuilder(name).
+		WithClientCtxKey(invalidRegistrationClientCtxKey(name)).
+		WithHttpClient(r.secureClient).
+		WithLedger(r.cfg.Ledger).
+		GenerateSignedClaims(authoriserBuilder).
+		PostClientRegister(r.cfg.OpenIDConfig.RegistrationEndpointAsString()).
+		CaptureRegisteredClient(authoriserBuilder)
+}
+
+// teardown deletes the software clients registered by the test cases built so far
+func (r *invalidRegistrations) teardown() []TestCase {
+	testCases := make([]TestCase, len(r.names))
+	for i, name := range r.names {
+		testCases[i] = teardownSoftwareClientTestCase(
+			r.cfg,
+			r.secureClient,
+			"Teardown software client: "+name,
+			invalidRegistrationClientCtxKey(name),
+		)
+	}
+	return testCases
+}
+
+func invalidRegistrationClientCtxKey(testCase string) string {
+	return clientCtxKey + ": " + testCase
+}
+
 func DCR32DeleteSoftwareClient(
 	cfg DCR32Config,
 	secureClient *http.Client,
@@ -137,11 +228,9 @@ func DCR32DeleteSoftwareClient(
 	name := "Delete software is supported"
 
 	if !cfg.DeleteImplemented {
-		return NewBuilder(
-			id,
-			fmt.Sprintf("(SKIP Delete endpoint not implemented) %s", name),
-			specLinkDeleteSoftware,
-		).Build()
+		return NewBuilder(id, name, specLinkDeleteSoftware).
+			Skip(skipReasonDeleteNotImplemented).
+			Build()
 	}
 
 	return NewBuilder(
@@ -157,80 +246,69 @@ func DCR32DeleteSoftwareClient(
 				ClientRetrieve(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeUnauthorized().
 				Build(),
-		).Build()
+		).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
+		Build()
 }
 
 func DCR32CreateInvalidRegistrationRequest(
 	cfg DCR32Config,
 	secureClient *http.Client,
 	authoriserBuilder auth.AuthoriserBuilder,
-) Scenario {
+) (Scenario, error) {
+	// RS256 is not allowed by the spec, EC signing keys can't sign it so a test RSA key signs instead
+	rs256AuthoriserBuilder := authoriserBuilder.WithTokenEndpointAuthMethod(jwt.SigningMethodRS256)
+	if _, isEC := cfg.PrivateKey.(*ecdsa.PrivateKey); isEC {
+		priv, err := generateRsaPrivateKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate RSA private key for test purposes: %v", err)
+		}
+		rs256AuthoriserBuilder = rs256AuthoriserBuilder.WithPrivateKey(priv)
+	}
+
+	registrations := newInvalidRegistrations(cfg, secureClient)
+	testCases := []TestCase{
+		registrations.
+			register("Register software client fails on expired claims", authoriserBuilder.WithJwtExpiration(-time.Hour)).
+			AssertStatusCodeBadRequest().
+			Build(),
+		registrations.
+			register("Register software client fails on invalid issuer", authoriserBuilder.WithIssuer("foo.is/invalid")).
+			AssertStatusCodeBadRequest().
+			Build(),
+		registrations.
+			register("Register software client fails on invalid issuer too short", authoriserBuilder.WithIssuer("")).
+			AssertStatusCodeBadRequest().
+			Build(),
+		registrations.
+			register(
+				"Register software client fails on invalid issuer too long",
+				authoriserBuilder.WithIssuer("123456789012345678901234567890"),
+			).
+			AssertStatusCodeBadRequest().
+			Build(),
+		registrations.
+			register("Register software client will fail with token endpoint auth method RS256", rs256AuthoriserBuilder).
+			AssertStatusCodeBadRequest().
+			Build(),
+		registrations.
+			register(
+				"Register software client fails on redirect_uri not in software_redirect_uris",
+				authoriserBuilder.WithRedirectURIs([]string{"https://abc.com"}),
+			).
+			AssertStatusCodeBadRequest().
+			AssertErrorMessage("invalid_redirect_uri", "invalid registration request redirect_uris value, must match or be a subset of the software_redirect_uris").
+			Build(),
+	}
+
 	return NewBuilder(
 		"DCR-004",
 		"Dynamically create a new software client will fail on invalid registration request",
 		specLinkRegisterSoftware,
 	).
-		TestCase(
-			NewTestCaseBuilder("Register software client fails on expired claims").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(
-					authoriserBuilder.
-						WithJwtExpiration(-time.Hour),
-				).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				Build(),
-		).
-		TestCase(
-			NewTestCaseBuilder("Register software client fails on invalid issuer").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(
-					authoriserBuilder.
-						WithIssuer("foo.is/invalid"),
-				).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				Build(),
-		).
-		TestCase(
-			NewTestCaseBuilder("Register software client fails on invalid issuer too short").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(
-					authoriserBuilder.
-						WithIssuer(""),
-				).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				Build(),
-		).
-		TestCase(
-			NewTestCaseBuilder("Register software client fails on invalid issuer too long").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(
-					authoriserBuilder.
-						WithIssuer("123456789012345678901234567890"),
-				).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				Build(),
-		).
-		TestCase(
-			NewTestCaseBuilder("Register software client will fail with token endpoint auth method RS256").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(authoriserBuilder.WithTokenEndpointAuthMethod(jwt.SigningMethodRS256)).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				Build(),
-		).
-		TestCase(
-			NewTestCaseBuilder("Register software client fails on redirect_uri not in software_redirect_uris").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(authoriserBuilder.WithRedirectURIs([]string{"https://abc.com"})).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				AssertErrorMessage("invalid_redirect_uri", "invalid registration request redirect_uris value, must match or be a subset of the software_redirect_uris").
-				Build(),
-		).Build()
+		TestCase(testCases...).
+		Teardown(registrations.teardown()...).
+		Build(), nil
 }
 
 func DCR32RetrieveSoftwareClient(
@@ -247,6 +325,7 @@ func DCR32RetrieveSoftwareClient(
 		TestCase(DCR32CreateSoftwareClientTestCases(cfg, secureClient, authoriserBuilder)...).
 		TestCase(DCR32RetrieveSoftwareClientTestCase(cfg, secureClient, validator)).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
 		Build()
 }
 
@@ -257,15 +336,13 @@ func DCR32RetrieveSoftwareClientTestCase(
 ) TestCase {
 	name := "Retrieve software client"
 	if !cfg.GetImplemented {
-		return NewTestCase(
-			fmt.Sprintf("(SKIP Get endpoint not implemented) %s", name),
-			[]step.Step{},
-		)
+		return NewSkippedTestCase(name, skipReasonGetNotImplemented)
 	}
 	return NewTestCaseBuilder("Retrieve software client").
 		WithHttpClient(secureClient).
 		ClientRetrieve(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 		AssertStatusCodeOk().
+		AssertContentTypeApplicationJson().
 		AssertValidSchemaResponse(validator).
 		ParseClientRetrieveResponse(cfg.OpenIDConfig.TokenEndpoint).
 		Build()
@@ -284,8 +361,10 @@ func DCR32RetrieveWithInvalidCredentials(
 		TestCase(
 			NewTestCaseBuilder("Register software client").
 				WithHttpClient(secureClient).
+				WithLedger(cfg.Ledger).
 				GenerateSignedClaims(authoriserBuilder).
 				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
+				CaptureRegisteredClient(authoriserBuilder).
 				AssertStatusCodeCreated().
 				ParseClientRegisterResponse(authoriserBuilder).
 				Build(),
@@ -304,6 +383,7 @@ func DCR32RetrieveWithInvalidCredentials(
 				Build(),
 		).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
 		Build()
 }
 
@@ -316,11 +396,9 @@ func DCR32UpdateSoftwareClient(
 	const name = "I should be able update a registered software"
 
 	if !cfg.PutImplemented {
-		return NewBuilder(
-			id,
-			fmt.Sprintf("(SKIP PUT endpoint not implemented) %s", name),
-			specLinkRetrieveSoftware,
-		).Build()
+		return NewBuilder(id, name, specLinkUpdateSoftware).
+			Skip(skipReasonPutNotImplemented).
+			Build()
 	}
 
 	return NewBuilder(
@@ -332,6 +410,7 @@ func DCR32UpdateSoftwareClient(
 		TestCase(
 			NewTestCaseBuilder("Update an existing software client").
 				WithHttpClient(secureClient).
+				WithLedger(cfg.Ledger).
 				GenerateSignedClaimsForRegistrationUpdate(authoriserBuilder).
 				ClientUpdate(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeOk().
@@ -339,6 +418,7 @@ func DCR32UpdateSoftwareClient(
 				Build(),
 		).
 		TestCase(DCR32DeleteSoftwareClientTestCase(cfg, secureClient)).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
 		Build()
 }
 
@@ -351,11 +431,9 @@ func DCR32UpdateSoftwareClientWithWrongId(
 	const name = "When I try to update a non existing software client I should be unauthorized"
 
 	if !cfg.PutImplemented {
-		return NewBuilder(
-			id,
-			fmt.Sprintf("(SKIP PUT endpoint not implemented) %s", name),
-			specLinkRetrieveSoftware,
-		).Build()
+		return NewBuilder(id, name, specLinkUpdateSoftware).
+			Skip(skipReasonPutNotImplemented).
+			Build()
 	}
 
 	return NewBuilder(
@@ -372,7 +450,9 @@ func DCR32UpdateSoftwareClientWithWrongId(
 				ClientUpdate(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeUnauthorized().
 				Build(),
-		).Build()
+		).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
+		Build()
 }
 
 func DCR32RetrieveSoftwareClientWrongId(
@@ -396,7 +476,9 @@ func DCR32RetrieveSoftwareClientWrongId(
 				ClientRetrieve(cfg.OpenIDConfig.RegistrationEndpointAsString()).
 				AssertStatusCodeUnauthorized().
 				Build(),
-		).Build()
+		).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
+		Build()
 }
 
 func DCR32RegisterSoftwareWrongResponseType(
@@ -415,14 +497,17 @@ func DCR32RegisterSoftwareWrongResponseType(
 		TestCase(
 			NewTestCaseBuilder("Register software client").
 				WithHttpClient(secureClient).
+				WithLedger(cfg.Ledger).
 				GenerateSignedClaims(
 					authoriserBuilder.WithResponseTypes([]string{"id_token", "token"}),
 				).
 				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
+				CaptureRegisteredClient(authoriserBuilder).
 				AssertStatusCodeBadRequest().
 				ParseClientRegisterResponse(authoriserBuilder).
 				Build(),
 		).
+		Teardown(DCR32TeardownSoftwareClientTestCase(cfg, secureClient)).
 		Build()
 }
 
@@ -434,28 +519,26 @@ func DCR32RegistrationRequestInvalidSignature(
 	id := "DCR-012"
 	const name = "When I try to register with a request which has an invalid signature it should fail"
 
-	// Use a test RSA key to sign the JWT, this must fail when checked by the server as the signature will not match one produced by the private key for the configured OBSeal
-	priv, err := generateRsaPrivateKey()
+	// Use a test key to sign the JWT, this must fail when checked by the server as the signature will not match one produced by the private key for the configured OBSeal
+	priv, err := generatePrivateKeyLike(cfg.PrivateKey)
 	if err != nil {
-		fmt.Errorf("failed to generate RSA private key for test purposes: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to generate private key for test purposes: %v", err)
 	}
 	authoriserBuilder = authoriserBuilder.WithPrivateKey(priv)
 
+	registrations := newInvalidRegistrations(cfg, secureClient)
+	testCase := registrations.
+		register("Register software client signed with wrong key", authoriserBuilder).
+		AssertStatusCodeBadRequest().
+		AssertErrorMessage("invalid_client_metadata", "Registration Request JWT is invalid: Expected JWT to have a valid signature").
+		Build()
+
 	return NewBuilder(
 		id,
 		name,
 		specLinkRegisterSoftware,
 	).
-		TestCase(
-			NewTestCaseBuilder("Register software client signed with wrong key").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(authoriserBuilder).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				AssertErrorMessage("invalid_client_metadata", "Registration Request JWT is invalid: Expected JWT to have a valid signature").
-				Build(),
-		).
+		TestCase(testCase).
 		// ToDo: This doesn't fail as expected as the jwt is not actually signed usign teh tokenEndpointSignMethod
 		//TestCase(
 		//		NewTestCaseBuilder("Register software client signed with unsupported alg none").
@@ -467,6 +550,7 @@ func DCR32RegistrationRequestInvalidSignature(
 		//			AssertErrorMessage("invalid_client_metadata", "registration JWT signature invalid").
 		//			Build(),
 		//	).
+		Teardown(registrations.teardown()...).
 		Build(), nil
 }
 
@@ -485,15 +569,13 @@ func DCR32RegisterInvalidSoftwareStatementSigning(
 
 	priv, err := generateRsaPrivateKey()
 	if err != nil {
-		fmt.Errorf("failed to generate RSA private key for test purposes: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to generate RSA private key for test purposes: %v", err)
 	}
 
 	// Re-sign the SSA with an unexpected key
 	ssaSignedWithWrongKey, err := token.SignedString(priv)
 	if err != nil {
-		fmt.Errorf("failed to sign software_statement jwt, err: %v", err)
-		return nil, err
+		return nil, fmt.Errorf("failed to sign software_statement jwt, err: %v", err)
 	}
 
 	// Create an SSA with no signature
@@ -502,36 +584,41 @@ func DCR32RegisterInvalidSoftwareStatementSigning(
 	delete(tokenWithNoneSign.Header, "kid")
 	ssaWithNoSig, err := tokenWithNoneSign.SigningString()
 
+	registrations := newInvalidRegistrations(cfg, secureClient)
+	testCases := []TestCase{
+		registrations.
+			register(
+				"Register software client, software_statement signed with wrong key",
+				authoriserBuilder.WithSSA(ssaSignedWithWrongKey),
+			).
+			AssertStatusCodeBadRequest().
+			AssertErrorMessage("invalid_software_statement", "Registration Request contains an invalid software_statement, Expected JWT to have a valid signature").
+			Build(),
+		registrations.
+			register("Register software client, software_statement none signing alg", authoriserBuilder.WithSSA(ssaWithNoSig)).
+			AssertStatusCodeBadRequest().
+			AssertErrorMessage("invalid_software_statement", "Registration Request contains an invalid software_statement, software_statement claim is not an encoded JWT").
+			Build(),
+	}
+
 	return NewBuilder(
 		id,
 		name,
 		specLinkRegisterSoftware,
 	).
-		TestCase(
-			NewTestCaseBuilder("Register software client, software_statement signed with wrong key").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(
-					authoriserBuilder.WithSSA(ssaSignedWithWrongKey),
-				).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				AssertErrorMessage("invalid_software_statement", "Registration Request contains an invalid software_statement, Expected JWT to have a valid signature").
-				Build(),
-		).
-		TestCase(
-			NewTestCaseBuilder("Register software client, software_statement none signing alg").
-				WithHttpClient(secureClient).
-				GenerateSignedClaims(
-					authoriserBuilder.WithSSA(ssaWithNoSig),
-				).
-				PostClientRegister(cfg.OpenIDConfig.RegistrationEndpointAsString()).
-				AssertStatusCodeBadRequest().
-				AssertErrorMessage("invalid_software_statement", "Registration Request contains an invalid software_statement, software_statement claim is not an encoded JWT").
-				Build(),
-		).
+		TestCase(testCases...).
+		Teardown(registrations.teardown()...).
 		Build(), nil
 }
 
 func generateRsaPrivateKey() (*rsa.PrivateKey, error) {
 	return rsa.GenerateKey(rand.Reader, 2048)
 }
+
+// generatePrivateKeyLike generates a random key of the same type as key, so it signs with the same algorithm
+func generatePrivateKeyLike(key crypto.Signer) (crypto.Signer, error) {
+	if ecKey, ok := key.(*ecdsa.PrivateKey); ok {
+		return ecdsa.GenerateKey(ecKey.Curve, rand.Reader)
+	}
+	return generateRsaPrivateKey()
+}
@@ -1,11 +1,13 @@
 package compliant
 
 import (
-	"crypto/rsa"
+	"crypto"
 	"crypto/x509"
 	"encoding/pem"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/certs"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
 	"github.com/dgrijalva/jwt-go"
 	"github.com/pkg/errors"
@@ -20,7 +22,7 @@ type DCR32Config struct {
 	KID                      string
 	RedirectURIs             []string
 	TokenSigningMethod       jwt.SigningMethod
-	PrivateKey               *rsa.PrivateKey
+	PrivateKey               crypto.Signer
 	SecureClient             *http2.Client
 	GetImplemented           bool
 	PutImplemented           bool
@@ -28,6 +30,7 @@ type DCR32Config struct {
 	AuthoriserBuilder        auth.AuthoriserBuilder
 	SchemaValidator          schema.Validator
 	CreateSoftwareClientOnly bool
+	Ledger                   step.ClientLedger
 }
 
 func NewDCR32Config(
@@ -45,10 +48,12 @@ func NewDCR32Config(
 	tlsSkipVerify bool,
 	specVersion string,
 	preferredTokenEndpointAuthMethod string,
+	preferredSigningAlg string,
+	jwksURI string,
 	createSoftwareClientOnly bool,
 	authorizationSignedResponseAlg string,
 ) (DCR32Config, error) {
-	privateKey, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(signingKeyPEM))
+	privateKey, err := certs.ParsePrivateKeyFromPEM([]byte(signingKeyPEM))
 	if err != nil {
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
@@ -63,7 +68,11 @@ func NewDCR32Config(
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
 
-	tokenSignMethod, err := responseTokenSignMethod(openIDConfig.TokenEndpointSigningAlgSupported)
+	tokenSignMethod, err := responseTokenSignMethod(
+		openIDConfig.TokenEndpointSigningAlgSupported,
+		preferredSigningAlg,
+		privateKey,
+	)
 	if err != nil {
 		return DCR32Config{}, errors.Wrap(err, "creating DCR32 config")
 	}
@@ -87,7 +96,8 @@ func NewDCR32Config(
 		WithTransportCert(transportCert).
 		WithTransportCertSubjectDn(transportCertSubjectDn).
 		WithPreferredTokenEndpointAuthMethod(preferredTokenEndpointAuthMethod).
-		WithAuthorizationSignedResponseAlg(authorizationSignedResponseAlg)
+		WithAuthorizationSignedResponseAlg(authorizationSignedResponseAlg).
+		WithJwksURI(jwksURI)
 
 	secureClient, err := http.NewBuilder().
 		WithRootCAs(transportRootCAs).
@@ -103,6 +113,7 @@ func NewDCR32Config(
 		SSA:                      ssa,
 		KID:                      kid,
 		RedirectURIs:             redirectURIs,
+		TokenSigningMethod:       tokenSignMethod,
 		PrivateKey:               privateKey,
 		SecureClient:             secureClient,
 		GetImplemented:           getImplemented,
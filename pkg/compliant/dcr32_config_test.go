@@ -36,6 +36,8 @@ func TestNewDCR32Config(t *testing.T) {
 		false,
 		"3.2",
 		"",
+		"",
+		"",
 		false,
 		"PS256",
 	)
@@ -1,9 +1,11 @@
 package compliant
 
 import (
+	"context"
 	"crypto/rand"
 	"crypto/rsa"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/schema"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/dgrijalva/jwt-go"
@@ -29,7 +31,7 @@ func TestNewDCR32(t *testing.T) {
 
 	assert.Equal(t, "1.0", manifest.Version())
 	assert.Equal(t, "DCR32", manifest.Name())
-	assert.Equal(t, 12, len(manifest.Scenarios()))
+	assert.Equal(t, 13, len(manifest.Scenarios()))
 }
 
 func TestDCR32ValidateOIDCConfigRegistrationURL(t *testing.T) {
@@ -43,6 +45,30 @@ func TestDCR32ValidateOIDCConfigRegistrationURL(t *testing.T) {
 	assert.Equal(t, specLinkDiscovery, scenario.Spec())
 }
 
+func TestDCR32ValidateTokenEndpointAuthMethod(t *testing.T) {
+	scenario := DCR32ValidateTokenEndpointAuthMethod(auth.NewAuthoriserBuilder())
+
+	assert.Equal(t, "DCR-014", scenario.Id())
+	assert.Equal(t, "Validate OIDC Config Token Endpoint Auth Methods", scenario.Name())
+	assert.Equal(t, specLinkDiscovery, scenario.Spec())
+}
+
+func TestDCR32ValidateTokenEndpointAuthMethod_FailsWithDiagnostic(t *testing.T) {
+	authoriserBuilder := auth.NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"none"}})
+	scenario := DCR32ValidateTokenEndpointAuthMethod(authoriserBuilder)
+
+	result := scenario.Run(context.Background())
+
+	assert.Equal(t, step.StatusFail, result.Status())
+	require.Len(t, result.TestCaseResults, 1)
+	assert.Contains(
+		t,
+		result.TestCaseResults[0].Results[0].FailReason,
+		"token_endpoint_auth_methods_supported advertises [none]",
+	)
+}
+
 func TestDCR32CreateSoftwareClient(t *testing.T) {
 	scenario := DCR32CreateSoftwareClient(
 		DCR32Config{DeleteImplemented: true},
@@ -76,21 +102,24 @@ func TestDCR32DeleteSoftwareClient_DeleteNotImplemented(t *testing.T) {
 		auth.NewAuthoriserBuilder(),
 	)
 
-	result := scenario.Run()
+	result := scenario.Run(context.Background())
 
 	assert.Equal(t, "DCR-003", scenario.Id())
-	name := "(SKIP Delete endpoint not implemented) Delete software is supported"
+	name := "Delete software is supported"
 	assert.Equal(t, name, scenario.Name())
 	assert.Equal(t, specLinkDeleteSoftware, scenario.Spec())
 	assert.False(t, result.Fail())
+	assert.Equal(t, step.StatusSkip, result.Status())
+	assert.Equal(t, "DELETE endpoint not implemented", result.SkipReason)
 }
 
 func TestDCR32CreateInvalidRegistrationRequest(t *testing.T) {
-	scenario := DCR32CreateInvalidRegistrationRequest(
+	scenario, err := DCR32CreateInvalidRegistrationRequest(
 		DCR32Config{GetImplemented: true},
 		&http.Client{},
 		auth.NewAuthoriserBuilder(),
 	)
+	require.NoError(t, err)
 
 	assert.Equal(t, "DCR-004", scenario.Id())
 	name := "Dynamically create a new software client will fail on invalid registration request"
@@ -139,9 +168,11 @@ func TestTCRetrieveSoftwareClient_GetNotImplemented(t *testing.T) {
 
 	result := tc.Run(step.NewContext())
 
-	assert.Equal(t, "(SKIP Get endpoint not implemented) Retrieve software client", result.Name)
+	assert.Equal(t, "Retrieve software client", result.Name)
 	assert.Equal(t, step.Results(nil), result.Results)
 	assert.False(t, result.Fail())
+	assert.Equal(t, step.StatusSkip, result.Status())
+	assert.Equal(t, "GET endpoint not implemented", result.SkipReason)
 }
 
 func TestDCR32RetrieveWithInvalidCredentials(t *testing.T) {
@@ -177,9 +208,14 @@ func TestDCR32UpdateSoftwareClientDisabled(t *testing.T) {
 		auth.NewAuthoriserBuilder(),
 	)
 
+	result := scenario.Run(context.Background())
+
 	assert.Equal(t, "DCR-008", scenario.Id())
-	name := "(SKIP PUT endpoint not implemented) I should be able update a registered software"
+	name := "I should be able update a registered software"
 	assert.Equal(t, name, scenario.Name())
+	assert.Equal(t, specLinkUpdateSoftware, scenario.Spec())
+	assert.Equal(t, step.StatusSkip, result.Status())
+	assert.Equal(t, "PUT endpoint not implemented", result.SkipReason)
 }
 
 func TestDCR32UpdateWrongId(t *testing.T) {
@@ -220,3 +256,28 @@ func TestDCR32RegisterWrongResponseTypes(t *testing.T) {
 	assert.Equal(t, name, scenario.Name())
 	assert.Equal(t, specLinkRegisterSoftware, scenario.Spec())
 }
+
+func TestDCR32TeardownSoftwareClientTestCase(t *testing.T) {
+	tc := DCR32TeardownSoftwareClientTestCase(
+		DCR32Config{DeleteImplemented: true},
+		&http.Client{},
+	)
+
+	result := tc.Run(step.NewContext())
+
+	assert.Equal(t, "Teardown software client", result.Name)
+	assert.False(t, result.Fail())
+	assert.Equal(t, step.StatusSkip, result.Status())
+}
+
+func TestDCR32TeardownSoftwareClientTestCase_DeleteNotImplemented(t *testing.T) {
+	tc := DCR32TeardownSoftwareClientTestCase(
+		DCR32Config{DeleteImplemented: false},
+		&http.Client{},
+	)
+
+	result := tc.Run(step.NewContext())
+
+	assert.Equal(t, step.StatusSkip, result.Status())
+	assert.Equal(t, "DELETE endpoint not implemented", result.SkipReason)
+}
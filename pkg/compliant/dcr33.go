@@ -5,11 +5,16 @@ func NewDCR33(cfg DCR32Config) (Manifest, error) {
 	authoriserBuilder := cfg.AuthoriserBuilder
 	validator := cfg.SchemaValidator
 
+	invalidRegistrationRequestScenario, err := DCR32CreateInvalidRegistrationRequest(cfg, secureClient, authoriserBuilder)
+	if err != nil {
+		return nil, err
+	}
 	scenarios := Scenarios{
 		DCR32ValidateOIDCConfigRegistrationURL(cfg),
+		DCR32ValidateTokenEndpointAuthMethod(authoriserBuilder),
 		DCR32CreateSoftwareClient(cfg, secureClient, authoriserBuilder),
 		DCR32DeleteSoftwareClient(cfg, secureClient, authoriserBuilder),
-		DCR32CreateInvalidRegistrationRequest(cfg, secureClient, authoriserBuilder),
+		invalidRegistrationRequestScenario,
 		DCR32RetrieveSoftwareClient(cfg, secureClient, authoriserBuilder, validator),
 		DCR32RetrieveWithInvalidCredentials(cfg, secureClient, authoriserBuilder),
 		DCR32UpdateSoftwareClient(cfg, secureClient, authoriserBuilder),
@@ -0,0 +1,97 @@
+package compliant
+
+import (
+	"context"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+)
+
+type EventType string
+
+const (
+	EventScenarioStarted  EventType = "scenario_started"
+	EventScenarioFinished EventType = "scenario_finished"
+	EventTestCaseStarted  EventType = "test_case_started"
+	EventTestCaseFinished EventType = "test_case_finished"
+	EventStepFinished     EventType = "step_finished"
+)
+
+// Event is emitted while a manifest runs, scenario fields identify the scenario it belongs to
+// as scenarios can run concurrently. Only the result matching the event type is set.
+type Event struct {
+	Type           EventType
+	ScenarioId     string
+	ScenarioName   string
+	TestCase       string
+	Teardown       bool
+	StepResult     step.Result
+	TestCaseResult TestCaseResult
+	ScenarioResult ScenarioResult
+}
+
+type EventListenerFunc func(event Event) error
+
+type eventCtxKey int
+
+const (
+	eventListenerCtxKey eventCtxKey = iota
+	eventScopeCtxKey
+)
+
+// eventScope is the scenario events are emitted for
+type eventScope struct {
+	scenarioId   string
+	scenarioName string
+	teardown     bool
+}
+
+// WithEventListener returns a context that makes a manifest run emit events to `listener`,
+// scenarios running concurrently may call it concurrently
+func WithEventListener(ctx context.Context, listener EventListenerFunc) context.Context {
+	return context.WithValue(ctx, eventListenerCtxKey, listener)
+}
+
+func withEventScope(ctx context.Context, scope eventScope) context.Context {
+	return context.WithValue(ctx, eventScopeCtxKey, scope)
+}
+
+// detachedContext keeps the values of its parent, emitting events and redacting like it,
+// but is never cancelled and has no deadline
+type detachedContext struct {
+	parent context.Context
+}
+
+func withoutCancel(ctx context.Context) context.Context {
+	return detachedContext{parent: ctx}
+}
+
+func (detachedContext) Deadline() (time.Time, bool) {
+	return time.Time{}, false
+}
+
+func (detachedContext) Done() <-chan struct{} {
+	return nil
+}
+
+func (detachedContext) Err() error {
+	return nil
+}
+
+func (c detachedContext) Value(key interface{}) interface{} {
+	return c.parent.Value(key)
+}
+
+func emit(ctx context.Context, event Event) {
+	listener, ok := ctx.Value(eventListenerCtxKey).(EventListenerFunc)
+	if !ok {
+		return
+	}
+	if scope, ok := ctx.Value(eventScopeCtxKey).(eventScope); ok {
+		event.ScenarioId = scope.scenarioId
+		event.ScenarioName = scope.scenarioName
+		event.Teardown = scope.teardown
+	}
+	// errors are collected by the listener owner, a sink failing doesn't stop the run
+	_ = listener(event)
+}
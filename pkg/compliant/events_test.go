@@ -0,0 +1,163 @@
+package compliant
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func eventsTestManifest(t *testing.T) Manifest {
+	scenarios := Scenarios{
+		NewBuilder("#1", "scenario one", "spec").
+			TestCase(NewTestCaseBuilder("test case").Step(failStep{}).Step(passStep{}).Build()).
+			TestCase(NewSkippedTestCase("skipped test case", "not implemented")).
+			Teardown(NewTestCaseBuilder("cleanup").Step(passStep{}).Build()).
+			Build(),
+		NewBuilder("#2", "scenario two", "spec").
+			Skip("not implemented").
+			Build(),
+	}
+	manifest, err := NewManifest("test", "1.0", scenarios)
+	require.NoError(t, err)
+	return manifest
+}
+
+func TestWithEventListener_EmitsEventsInOrder(t *testing.T) {
+	var events []Event
+	ctx := WithEventListener(context.Background(), func(event Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	eventsTestManifest(t).Run(ctx)
+
+	var types []EventType
+	for _, event := range events {
+		types = append(types, event.Type)
+	}
+	expected := []EventType{
+		EventScenarioStarted,
+		EventTestCaseStarted,
+		EventStepFinished,
+		EventStepFinished,
+		EventTestCaseFinished,
+		EventTestCaseStarted,
+		EventTestCaseFinished,
+		EventTestCaseStarted,
+		EventStepFinished,
+		EventTestCaseFinished,
+		EventScenarioFinished,
+		EventScenarioStarted,
+		EventScenarioFinished,
+	}
+	assert.Equal(t, expected, types)
+
+	assert.Equal(t, "#1", events[2].ScenarioId)
+	assert.Equal(t, "scenario one", events[2].ScenarioName)
+	assert.Equal(t, "test case", events[2].TestCase)
+	assert.Equal(t, step.StatusFail, events[2].StepResult.Status())
+	assert.Equal(t, step.StatusBlocked, events[3].StepResult.Status())
+	assert.False(t, events[4].Teardown)
+	assert.Equal(t, "cleanup", events[7].TestCase)
+	assert.True(t, events[7].Teardown)
+	assert.Equal(t, "#2", events[12].ScenarioResult.Id)
+}
+
+func TestPrinter_PrintEvent_MatchesPrint(t *testing.T) {
+	live := &bytes.Buffer{}
+	tester := NewTester()
+	tester.AddEventListener(NewPrinterWithOptions(false, live).PrintEvent)
+	var result ManifestResult
+	tester.AddListener(func(r ManifestResult) error {
+		result = r
+		return nil
+	})
+
+	_, err := tester.Compliant(context.Background(), eventsTestManifest(t))
+	require.NoError(t, err)
+
+	batch := &bytes.Buffer{}
+	require.NoError(t, NewPrinterWithOptions(false, batch).Print(result))
+	assert.Equal(t, batch.String(), live.String())
+}
+
+func TestPrinter_PrintFinishedScenario(t *testing.T) {
+	grouped := &bytes.Buffer{}
+	tester := NewTester()
+	tester.AddEventListener(NewPrinterWithOptions(false, grouped).PrintFinishedScenario)
+	var result ManifestResult
+	tester.AddListener(func(r ManifestResult) error {
+		result = r
+		return nil
+	})
+
+	_, err := tester.Compliant(context.Background(), eventsTestManifest(t))
+	require.NoError(t, err)
+
+	batch := &bytes.Buffer{}
+	require.NoError(t, NewPrinterWithOptions(false, batch).Print(result))
+	assert.Equal(t, batch.String(), grouped.String())
+}
+
+func TestTester_EventListenerErrorIsReturned(t *testing.T) {
+	tester := NewTester()
+	calls := 0
+	tester.AddEventListener(func(event Event) error {
+		calls++
+		return errors.New("sink unavailable")
+	})
+
+	_, err := tester.Compliant(context.Background(), eventsTestManifest(t))
+
+	assert.EqualError(t, err, "sink unavailable")
+	assert.Equal(t, 1, calls)
+}
+
+func TestTester_EventListenerErrorDoesNotStopOtherListeners(t *testing.T) {
+	tester := NewTester()
+	failingCalls := 0
+	tester.AddEventListener(func(event Event) error {
+		failingCalls++
+		return errors.New("sink unavailable")
+	})
+	var events []Event
+	tester.AddEventListener(func(event Event) error {
+		events = append(events, event)
+		return nil
+	})
+
+	_, err := tester.Compliant(context.Background(), eventsTestManifest(t))
+
+	assert.EqualError(t, err, "sink unavailable")
+	assert.Equal(t, 1, failingCalls)
+	var expected []Event
+	_, err = NewTester().Compliant(
+		WithEventListener(context.Background(), func(event Event) error {
+			expected = append(expected, event)
+			return nil
+		}),
+		eventsTestManifest(t),
+	)
+	require.NoError(t, err)
+	assert.Len(t, events, len(expected))
+}
+
+func TestWithoutCancel_KeepsValuesAfterCancel(t *testing.T) {
+	type ctxKey string
+	parent, cancel := context.WithTimeout(context.WithValue(context.Background(), ctxKey("key"), "value"), time.Hour)
+	cancel()
+
+	detached := withoutCancel(parent)
+
+	assert.Equal(t, "value", detached.Value(ctxKey("key")))
+	assert.Nil(t, detached.Done())
+	assert.NoError(t, detached.Err())
+	_, ok := detached.Deadline()
+	assert.False(t, ok)
+}
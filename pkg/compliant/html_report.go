@@ -0,0 +1,170 @@
+package compliant
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+)
+
+type htmlReport struct {
+	Report
+	Config RunConfig
+}
+
+// writeHTML renders the report as a single html file with no external resources,
+// test cases, steps and debug messages are collapsible
+func (r reporter) writeHTML(w io.Writer, result ManifestResult) error {
+	report := r.mapToReport(result)
+	if r.debug {
+		addDebugToReport(&report, result)
+	}
+
+	tmpl, err := template.New("report").Funcs(template.FuncMap{
+		"lower": func(status step.Status) string {
+			return strings.ToLower(string(status))
+		},
+		"duration": func(ms float64) string {
+			return duration(time.Duration(ms * float64(time.Millisecond))).String()
+		},
+	}).Parse(htmlReportTemplate)
+	if err != nil {
+		return err
+	}
+
+	return tmpl.Execute(w, htmlReport{
+		Report: report,
+		Config: r.config,
+	})
+}
+
+// addDebugToReport copies the debug messages of each step into the report, the report keeps the
+// order of the results so they are matched by position
+func addDebugToReport(report *Report, result ManifestResult) {
+	for i, scenario := range result.Results {
+		addDebugToTestCases(report.Scenarios[i].TestCases, scenario.TestCaseResults)
+		addDebugToTestCases(report.Scenarios[i].Teardown, scenario.TeardownResults)
+	}
+}
+
+func addDebugToTestCases(reportTestCases []ReportTestcase, results TestCaseResults) {
+	for j, testCase := range results {
+		for k, stepResult := range testCase.Results {
+			var debug []string
+			for _, message := range stepResult.Debug.Item {
+				debug = append(debug, fmt.Sprintf("%s %s", message.Time.Format(time.RFC3339), message.Message))
+			}
+			reportTestCases[j].Steps[k].Debug = debug
+		}
+	}
+}
+
+const htmlReportTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Name }} {{ .Version }} conformance report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; vertical-align: top; }
+th { background: #f2f2f2; }
+details { margin: 0.3em 0 0.3em 1em; }
+summary { cursor: pointer; }
+pre { background: #f7f7f7; padding: 0.5em; white-space: pre-wrap; word-break: break-all; }
+.status { font-weight: bold; }
+.pass { color: #1a7f37; }
+.fail { color: #cf222e; }
+.skip { color: #9a6700; }
+.blocked { color: #8250df; }
+</style>
+</head>
+<body>
+<h1>{{ .Name }} {{ .Version }} conformance report</h1>
+<table>
+<tr><th>Result</th><td class="status {{ if .Pass }}pass{{ else }}fail{{ end }}">{{ if .Pass }}PASS{{ else }}FAIL{{ end }}</td></tr>
+<tr><th>Teardown</th><td class="status {{ if .TeardownPass }}pass{{ else }}fail{{ end }}">{{ if .TeardownPass }}PASS{{ else }}FAIL{{ end }}</td></tr>
+{{- if .Aborted }}
+<tr><th>Aborted</th><td class="status fail">{{ .AbortReason }}</td></tr>
+{{- end }}
+<tr><th>Brand</th><td>{{ .Config.Brand }}</td></tr>
+<tr><th>Environment</th><td>{{ .Config.Environment }}</td></tr>
+<tr><th>Well-known endpoint</th><td>{{ .Config.WellknownEndpoint }}</td></tr>
+</table>
+
+<h2>Summary</h2>
+<table>
+<tr><th>Id</th><th>Scenario</th><th>Spec</th><th>Status</th><th>Duration</th></tr>
+{{- range .Scenarios }}
+<tr>
+<td><a href="#{{ .Id }}">{{ .Id }}</a></td>
+<td>{{ .Name }}</td>
+<td><a href="{{ .Spec }}">spec</a></td>
+<td class="status {{ lower .Status }}">{{ .Status }}</td>
+<td>{{ duration .DurationMs }}</td>
+</tr>
+{{- end }}
+</table>
+{{- if .Latency }}
+
+<h2>Latency per endpoint</h2>
+<table>
+<tr><th>Endpoint</th><th>Count</th><th>Min (ms)</th><th>Mean (ms)</th><th>p95 (ms)</th><th>Max (ms)</th></tr>
+{{- range .Latency }}
+<tr><td>{{ .Endpoint }}</td><td>{{ .Count }}</td><td>{{ .MinMs }}</td><td>{{ .MeanMs }}</td><td>{{ .P95Ms }}</td><td>{{ .MaxMs }}</td></tr>
+{{- end }}
+</table>
+{{- end }}
+
+<h2>Scenarios</h2>
+{{- range .Scenarios }}
+<h3 id="{{ .Id }}">{{ .Id }} - {{ .Name }} <span class="status {{ lower .Status }}">{{ .Status }}</span></h3>
+<p><a href="{{ .Spec }}">{{ .Spec }}</a></p>
+{{- if .SkipReason }}
+<p class="skip">{{ .SkipReason }}</p>
+{{- end }}
+{{- range .TestCases }}
+{{ template "testcase" . }}
+{{- end }}
+{{- if .Teardown }}
+<h4>Teardown</h4>
+{{- range .Teardown }}
+{{ template "testcase" . }}
+{{- end }}
+{{- end }}
+{{- end }}
+</body>
+</html>
+{{ define "testcase" -}}
+<details>
+<summary>{{ .Name }} <span class="status {{ lower .Status }}">{{ .Status }}</span> {{ duration .DurationMs }}</summary>
+{{- if .SkipReason }}
+<p class="skip">{{ .SkipReason }}</p>
+{{- end }}
+{{- range .Steps }}
+<details>
+<summary><span class="status {{ lower .Status }}">{{ .Status }}</span> {{ .Name }} {{ duration .DurationMs }}</summary>
+{{- if .Reason }}
+<p class="fail">{{ .Reason }}</p>
+{{- end }}
+{{- if .SkipReason }}
+<p class="skip">{{ .SkipReason }}</p>
+{{- end }}
+{{- if .BlockedBy }}
+<p class="blocked">blocked by {{ .BlockedBy }}</p>
+{{- end }}
+{{- if .Debug }}
+<details>
+<summary>Debug</summary>
+<pre>{{ range .Debug }}{{ . }}
+{{ end }}</pre>
+</details>
+{{- end }}
+</details>
+{{- end }}
+</details>
+{{- end }}
+`
@@ -0,0 +1,118 @@
+package compliant
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReporter_WriteHTML(t *testing.T) {
+	result := ManifestResult{
+		Results: []ScenarioResult{
+			{
+				Id:   "1",
+				Name: "scenario one",
+				Spec: "spec link",
+				TestCaseResults: TestCaseResults{
+					{
+						Name: "tc one",
+						Results: []step.Result{
+							{
+								Name:       "step one",
+								Pass:       false,
+								FailReason: "reasons",
+								Timing:     timing(250 * time.Millisecond),
+								Requests: []step.RequestTiming{
+									{
+										Endpoint:   step.EndpointRegister,
+										StatusCode: 400,
+										Timing:     timing(240 * time.Millisecond),
+									},
+								},
+								Debug: step.DebugMessages{
+									Item: []step.DebugMessage{
+										{
+											Message: "debug",
+										},
+									},
+								},
+							},
+							{
+								Name:      "step two",
+								Blocked:   true,
+								BlockedBy: "tc one: step one",
+							},
+						},
+					},
+				},
+				TeardownResults: TestCaseResults{
+					{
+						Name: "teardown",
+						Results: []step.Result{
+							{
+								Name:   "delete client",
+								Pass:   true,
+								Timing: timing(120 * time.Millisecond),
+								Requests: []step.RequestTiming{
+									{
+										Endpoint:   step.EndpointDelete,
+										StatusCode: 204,
+										Timing:     timing(110 * time.Millisecond),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Id:         "2",
+				Name:       "scenario two",
+				Spec:       "spec link",
+				SkipReason: "endpoint not implemented",
+			},
+			{
+				Id:   "3",
+				Name: "scenario three",
+				Spec: "spec link",
+				TestCaseResults: TestCaseResults{
+					{
+						Name:       "tc skipped",
+						SkipReason: "endpoint not implemented",
+					},
+				},
+			},
+		},
+		Name:    "manifest test test result",
+		Version: "0.0",
+	}
+	config := RunConfig{
+		WellknownEndpoint: "https://aspsp/.well-known/openid-configuration",
+		Environment:       "sandbox",
+		Brand:             "brand <&>",
+	}
+	w := &bytes.Buffer{}
+	reporter := NewReporter(config, true, nil, "")
+
+	err := reporter.writeHTML(w, result)
+	require.NoError(t, err)
+
+	gp := filepath.Join("testdata", t.Name()+".golden.html")
+
+	if *update {
+		t.Log("update golden file")
+		err = ioutil.WriteFile(gp, w.Bytes(), 0644)
+		require.NoError(t, err)
+	}
+
+	g, err := ioutil.ReadFile(gp)
+	require.NoError(t, err)
+
+	assert.Equal(t, string(g), w.String())
+}
@@ -0,0 +1,217 @@
+package compliant
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+)
+
+func NewJUnitReporter(w io.Writer) junitReporter {
+	return junitReporter{output: w}
+}
+
+// junitReporter writes the result as JUnit XML, scenarios are test suites and test cases are test cases.
+// Teardown test cases are included but their failures are written to system-err only,
+// as they don't affect the conformance result.
+type junitReporter struct {
+	output io.Writer
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Id        string          `xml:"id,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr,omitempty"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+func (r junitReporter) Report(result ManifestResult) error {
+	suites := junitTestSuites{Name: result.Name}
+	var duration time.Duration
+	for _, scenario := range result.Results {
+		suite := r.mapScenario(scenario)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Skipped += suite.Skipped
+		duration += scenario.Duration()
+		suites.Suites = append(suites.Suites, suite)
+	}
+	suites.Time = seconds(duration)
+
+	_, err := io.WriteString(r.output, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(r.output)
+	encoder.Indent("", " ")
+	if err = encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err = io.WriteString(r.output, "\n")
+	return err
+}
+
+func (r junitReporter) mapScenario(scenario ScenarioResult) junitTestSuite {
+	suite := junitTestSuite{
+		Name:      fmt.Sprintf("%s - %s", scenario.Id, scenario.Name),
+		Id:        scenario.Id,
+		Time:      seconds(scenario.Duration()),
+		Timestamp: junitTimestamp(scenario.Start),
+	}
+
+	if scenario.SkipReason != "" {
+		suite.TestCases = []junitTestCase{
+			{
+				Name:      scenario.Name,
+				ClassName: scenario.Id,
+				Time:      seconds(0),
+				Skipped:   &junitMessage{Message: scenario.SkipReason},
+			},
+		}
+	}
+
+	for _, testCase := range scenario.TestCaseResults {
+		suite.TestCases = append(suite.TestCases, r.mapTestCase(scenario.Id, testCase))
+	}
+	for _, testCase := range scenario.TeardownResults {
+		suite.TestCases = append(suite.TestCases, r.mapTeardown(scenario.Id, testCase))
+	}
+
+	for _, testCase := range suite.TestCases {
+		suite.Tests++
+		if testCase.Failure != nil {
+			suite.Failures++
+		}
+		if testCase.Skipped != nil {
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+func (r junitReporter) mapTestCase(scenarioId string, result TestCaseResult) junitTestCase {
+	testCase := junitTestCase{
+		Name:      result.Name,
+		ClassName: scenarioId,
+		Time:      seconds(result.Duration()),
+		SystemOut: junitDebug(result.Results),
+	}
+
+	switch result.Status() {
+	case step.StatusFail:
+		var reasons []string
+		for _, stepResult := range result.Results {
+			if stepResult.Status() == step.StatusFail {
+				reasons = append(reasons, fmt.Sprintf("%s: %s", stepResult.Name, stepResult.FailReason))
+			}
+		}
+		testCase.Failure = &junitMessage{
+			Message: reasons[0],
+			Type:    string(step.StatusFail),
+			Text:    strings.Join(reasons, "\n"),
+		}
+	case step.StatusSkip:
+		testCase.Skipped = &junitMessage{Message: testCaseSkipReason(result)}
+	case step.StatusBlocked:
+		testCase.Skipped = &junitMessage{Message: fmt.Sprintf("blocked by %s", testCaseBlockedBy(result))}
+	}
+	return testCase
+}
+
+func (r junitReporter) mapTeardown(scenarioId string, result TestCaseResult) junitTestCase {
+	testCase := junitTestCase{
+		Name:      fmt.Sprintf("Teardown: %s", result.Name),
+		ClassName: scenarioId,
+		Time:      seconds(result.Duration()),
+		SystemOut: junitDebug(result.Results),
+	}
+
+	var reasons []string
+	for _, stepResult := range result.Results {
+		if stepResult.Status() == step.StatusFail {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", stepResult.Name, stepResult.FailReason))
+		}
+	}
+	testCase.SystemErr = strings.Join(reasons, "\n")
+
+	if result.Status() == step.StatusSkip {
+		testCase.Skipped = &junitMessage{Message: testCaseSkipReason(result)}
+	}
+	return testCase
+}
+
+func testCaseSkipReason(result TestCaseResult) string {
+	if result.SkipReason != "" {
+		return result.SkipReason
+	}
+	for _, stepResult := range result.Results {
+		if stepResult.SkipReason != "" {
+			return stepResult.SkipReason
+		}
+	}
+	return ""
+}
+
+func testCaseBlockedBy(result TestCaseResult) string {
+	for _, stepResult := range result.Results {
+		if stepResult.BlockedBy != "" {
+			return stepResult.BlockedBy
+		}
+	}
+	return ""
+}
+
+// junitDebug lists the debug messages of all steps, prefixed with the step name
+func junitDebug(results step.Results) string {
+	var lines []string
+	for _, result := range results {
+		for _, message := range result.Debug.Item {
+			lines = append(lines, fmt.Sprintf("%s %s: %s", message.Time.Format(time.RFC3339), result.Name, message.Message))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func junitTimestamp(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format("2006-01-02T15:04:05")
+}
+
+func seconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
@@ -0,0 +1,88 @@
+package compliant
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+)
+
+// EndpointLatency summarises the response times of an ASPSP endpoint over a run
+type EndpointLatency struct {
+	Endpoint string
+	Count    int
+	Min      time.Duration
+	Mean     time.Duration
+	P95      time.Duration
+	Max      time.Duration
+}
+
+// Latencies summarises the response times of every endpoint called during the run, including teardown
+func (r ManifestResult) Latencies() []EndpointLatency {
+	durations := map[string][]time.Duration{}
+	for _, scenario := range r.Results {
+		testCases := append(TestCaseResults{}, scenario.TestCaseResults...)
+		testCases = append(testCases, scenario.TeardownResults...)
+		for _, testCase := range testCases {
+			for _, result := range testCase.Results {
+				for _, request := range result.Requests {
+					durations[request.Endpoint] = append(durations[request.Endpoint], request.Duration())
+				}
+			}
+		}
+	}
+
+	latencies := make([]EndpointLatency, 0, len(durations))
+	for endpoint, endpointDurations := range durations {
+		latencies = append(latencies, newEndpointLatency(endpoint, endpointDurations))
+	}
+	sort.Slice(latencies, func(i, j int) bool {
+		iOrder, jOrder := endpointOrder(latencies[i].Endpoint), endpointOrder(latencies[j].Endpoint)
+		if iOrder != jOrder {
+			return iOrder < jOrder
+		}
+		return latencies[i].Endpoint < latencies[j].Endpoint
+	})
+	return latencies
+}
+
+func newEndpointLatency(endpoint string, durations []time.Duration) EndpointLatency {
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+
+	var total time.Duration
+	for _, duration := range durations {
+		total += duration
+	}
+
+	// nearest rank percentile
+	p95 := int(math.Ceil(0.95*float64(len(durations)))) - 1
+
+	return EndpointLatency{
+		Endpoint: endpoint,
+		Count:    len(durations),
+		Min:      durations[0],
+		Mean:     total / time.Duration(len(durations)),
+		P95:      durations[p95],
+		Max:      durations[len(durations)-1],
+	}
+}
+
+// endpointOrder lists DCR endpoints first, other endpoints follow by name
+func endpointOrder(endpoint string) int {
+	switch endpoint {
+	case step.EndpointRegister:
+		return 0
+	case step.EndpointRetrieve:
+		return 1
+	case step.EndpointUpdate:
+		return 2
+	case step.EndpointDelete:
+		return 3
+	case step.EndpointToken:
+		return 4
+	}
+	return 5
+}
@@ -0,0 +1,58 @@
+package compliant
+
+import (
+	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestTiming(endpoint string, d time.Duration) step.RequestTiming {
+	return step.RequestTiming{Endpoint: endpoint, Timing: timing(d)}
+}
+
+func TestManifestResult_Latencies(t *testing.T) {
+	var registerRequests []step.RequestTiming
+	for i := 1; i <= 20; i++ {
+		registerRequests = append(registerRequests, requestTiming(step.EndpointRegister, time.Duration(i)*time.Millisecond))
+	}
+	result := ManifestResult{
+		Results: []ScenarioResult{
+			{
+				TestCaseResults: TestCaseResults{
+					{Results: step.Results{
+						{Requests: []step.RequestTiming{requestTiming("GET https://aspsp/.well-known", time.Second)}},
+						{Requests: []step.RequestTiming{requestTiming(step.EndpointToken, 30*time.Millisecond)}},
+						{Requests: registerRequests},
+					}},
+				},
+				TeardownResults: TestCaseResults{
+					{Results: step.Results{
+						{Requests: []step.RequestTiming{requestTiming(step.EndpointDelete, 10*time.Millisecond)}},
+					}},
+				},
+			},
+		},
+	}
+
+	latencies := result.Latencies()
+
+	require.Len(t, latencies, 4)
+	assert.Equal(t, EndpointLatency{
+		Endpoint: step.EndpointRegister,
+		Count:    20,
+		Min:      time.Millisecond,
+		Mean:     10500 * time.Microsecond,
+		P95:      19 * time.Millisecond,
+		Max:      20 * time.Millisecond,
+	}, latencies[0])
+	assert.Equal(t, step.EndpointDelete, latencies[1].Endpoint)
+	assert.Equal(t, step.EndpointToken, latencies[2].Endpoint)
+	assert.Equal(t, "GET https://aspsp/.well-known", latencies[3].Endpoint)
+}
+
+func TestManifestResult_Latencies_NoRequests(t *testing.T) {
+	assert.Empty(t, ManifestResult{}.Latencies())
+}
@@ -0,0 +1,138 @@
+package ledger
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/pkg/errors"
+)
+
+// Entry is a software client registered during a run that has not been confirmed deleted
+type Entry struct {
+	ClientId                string    `json:"client_id"`
+	RegistrationAccessToken string    `json:"registration_access_token"`
+	RegistrationEndpoint    string    `json:"registration_endpoint"`
+	CreatedAt               time.Time `json:"created_at"`
+}
+
+type file struct {
+	Clients []Entry `json:"clients"`
+}
+
+// Ledger persists registered software clients in a local json file, so they can be deleted
+// when a run is interrupted and the clients held in memory are lost
+type Ledger struct {
+	path                 string
+	registrationEndpoint string
+	mutex                *sync.Mutex
+}
+
+func NewLedger(path, registrationEndpoint string) Ledger {
+	return Ledger{
+		path:                 path,
+		registrationEndpoint: registrationEndpoint,
+		mutex:                &sync.Mutex{},
+	}
+}
+
+// Add records a software client registered on the ledger registration endpoint,
+// an existing entry for the same client is replaced
+func (l Ledger) Add(c client.Client) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entries, err := l.read()
+	if err != nil {
+		return errors.Wrap(err, "adding client to ledger")
+	}
+
+	entries = without(entries, c.Id())
+	entries = append(entries, Entry{
+		ClientId:                c.Id(),
+		RegistrationAccessToken: c.RegistrationAccessToken(),
+		RegistrationEndpoint:    l.registrationEndpoint,
+		CreatedAt:               time.Now().UTC(),
+	})
+
+	return errors.Wrap(l.write(entries), "adding client to ledger")
+}
+
+// Remove deletes the entry for a software client, removing a client not in the ledger is not an error
+func (l Ledger) Remove(c client.Client) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entries, err := l.read()
+	if err != nil {
+		return errors.Wrap(err, "removing client from ledger")
+	}
+
+	return errors.Wrap(l.write(without(entries, c.Id())), "removing client from ledger")
+}
+
+// Entries returns all software clients in the ledger
+func (l Ledger) Entries() ([]Entry, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	entries, err := l.read()
+	if err != nil {
+		return nil, errors.Wrap(err, "reading ledger entries")
+	}
+	return entries, nil
+}
+
+func (l Ledger) read() ([]Entry, error) {
+	content, err := ioutil.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var f file
+	if err = json.Unmarshal(content, &f); err != nil {
+		return nil, err
+	}
+	return f.Clients, nil
+}
+
+// write replaces the ledger file through a temporary file so a crash never leaves it half written
+func (l Ledger) write(entries []Entry) error {
+	content, err := json.MarshalIndent(file{Clients: entries}, "", " ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := ioutil.TempFile(filepath.Dir(l.path), filepath.Base(l.path))
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), l.path)
+}
+
+func without(entries []Entry, clientId string) []Entry {
+	filtered := []Entry{}
+	for _, entry := range entries {
+		if entry.ClientId != clientId {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
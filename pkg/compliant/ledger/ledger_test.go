@@ -0,0 +1,82 @@
+package ledger
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func tempLedgerPath(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "ledger")
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		os.RemoveAll(dir)
+	})
+	return filepath.Join(dir, "ledger.json")
+}
+
+func TestLedger_Entries_EmptyWhenFileMissing(t *testing.T) {
+	ledger := NewLedger(tempLedgerPath(t), "https://aspsp/register")
+
+	entries, err := ledger.Entries()
+
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestLedger_Add(t *testing.T) {
+	ledger := NewLedger(tempLedgerPath(t), "https://aspsp/register")
+
+	err := ledger.Add(client.NewRegisteredClient("id", "token"))
+	require.NoError(t, err)
+
+	entries, err := ledger.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "id", entries[0].ClientId)
+	assert.Equal(t, "token", entries[0].RegistrationAccessToken)
+	assert.Equal(t, "https://aspsp/register", entries[0].RegistrationEndpoint)
+	assert.False(t, entries[0].CreatedAt.IsZero())
+}
+
+func TestLedger_Add_ReplacesExistingClient(t *testing.T) {
+	ledger := NewLedger(tempLedgerPath(t), "https://aspsp/register")
+
+	require.NoError(t, ledger.Add(client.NewRegisteredClient("id", "token")))
+	require.NoError(t, ledger.Add(client.NewRegisteredClient("id", "new token")))
+
+	entries, err := ledger.Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "new token", entries[0].RegistrationAccessToken)
+}
+
+func TestLedger_Remove(t *testing.T) {
+	path := tempLedgerPath(t)
+	ledger := NewLedger(path, "https://aspsp/register")
+	require.NoError(t, ledger.Add(client.NewRegisteredClient("one", "token")))
+	require.NoError(t, ledger.Add(client.NewRegisteredClient("two", "token")))
+
+	err := ledger.Remove(client.NewRegisteredClient("one", "token"))
+	require.NoError(t, err)
+
+	// a new ledger on the same file sees the persisted state
+	entries, err := NewLedger(path, "").Entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "two", entries[0].ClientId)
+}
+
+func TestLedger_Entries_HandlesInvalidFile(t *testing.T) {
+	path := tempLedgerPath(t)
+	require.NoError(t, ioutil.WriteFile(path, []byte("not json"), 0600))
+
+	_, err := NewLedger(path, "").Entries()
+
+	assert.Error(t, err)
+}
@@ -1,13 +1,15 @@
 package compliant
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"strings"
+	"sync"
 )
 
 type Manifest interface {
-	Run() ManifestResult
+	Run(ctx context.Context) ManifestResult
 	Scenarios() Scenarios
 	Name() string
 	Version() string
@@ -43,16 +45,29 @@ func scenarioIdDuplicated(id string, scenarios Scenarios) bool {
 	return count != 1
 }
 
-func (s versionedManifest) Run() ManifestResult {
+func (s versionedManifest) Run(ctx context.Context) ManifestResult {
 	results := make([]ScenarioResult, len(s.scenarios))
 	for key, scenario := range s.scenarios {
-		results[key] = scenario.Run()
+		results[key] = scenario.Run(ctx)
 	}
-	return ManifestResult{
+	return newManifestResult(s, results)
+}
+
+// newManifestResult marks the result as aborted when any scenario was cut short by the run ending,
+// a run ending once all scenarios completed is not aborted
+func newManifestResult(manifest Manifest, results []ScenarioResult) ManifestResult {
+	result := ManifestResult{
 		Results: results,
-		Name:    s.name,
-		Version: s.version,
+		Name:    manifest.Name(),
+		Version: manifest.Version(),
 	}
+	for _, scenario := range results {
+		if scenario.AbortReason != "" {
+			result.AbortReason = scenario.AbortReason
+			break
+		}
+	}
+	return result
 }
 
 func (s versionedManifest) Scenarios() Scenarios {
@@ -67,10 +82,58 @@ func (s versionedManifest) Version() string {
 	return s.version
 }
 
+type parallelManifest struct {
+	Manifest
+	workers int
+}
+
+// NewParallelManifest runs the scenarios of a manifest concurrently on a pool of `workers`,
+// results keep the order of the scenarios in the manifest
+func NewParallelManifest(manifest Manifest, workers int) (Manifest, error) {
+	if workers < 1 {
+		return nil, errors.New("parallel workers must be at least 1")
+	}
+	return parallelManifest{
+		Manifest: manifest,
+		workers:  workers,
+	}, nil
+}
+
+func (s parallelManifest) Run(ctx context.Context) ManifestResult {
+	scenarios := s.Scenarios()
+	results := make([]ScenarioResult, len(scenarios))
+
+	jobs := make(chan int)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range jobs {
+				results[key] = scenarios[key].Run(ctx)
+			}
+		}()
+	}
+
+	for key := range scenarios {
+		jobs <- key
+	}
+	close(jobs)
+	wg.Wait()
+
+	return newManifestResult(s, results)
+}
+
 type ManifestResult struct {
 	Results []ScenarioResult
 	Name    string
 	Version string
+	// AbortReason is set when the run was cancelled or timed out before all scenarios completed
+	AbortReason string
+}
+
+func (r ManifestResult) Aborted() bool {
+	return r.AbortReason != ""
 }
 
 func (r ManifestResult) Fail() bool {
@@ -82,6 +145,12 @@ func (r ManifestResult) Fail() bool {
 	return false
 }
 
+// TeardownFail is true when cleaning up after any of the scenarios failed,
+// it is reported apart from the conformance result
+func (r ManifestResult) TeardownFail() bool {
+	return ScenariosResult(r.Results).TeardownFail()
+}
+
 func NewFilteredManifest(manifest Manifest, expression string) (Manifest, error) {
 	scenarios := manifest.Scenarios()
 
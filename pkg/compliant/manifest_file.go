@@ -0,0 +1,283 @@
+package compliant
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// step types of a manifest file
+const (
+	manifestStepRegister           = "register"
+	manifestStepParseClient        = "parse_client"
+	manifestStepRetrieve           = "retrieve"
+	manifestStepUpdate             = "update"
+	manifestStepDelete             = "delete"
+	manifestStepCredentialsGrant   = "credentials_grant"
+	manifestStepAssertStatus       = "assert_status"
+	manifestStepAssertContentType  = "assert_content_type"
+	manifestStepAssertErrorMessage = "assert_error_message"
+	manifestStepSchemaValidate     = "schema_validate"
+)
+
+// methods a scenario of a manifest file can require, it is skipped when they are not implemented
+var manifestSkipReasons = map[string]string{
+	"get":    skipReasonGetNotImplemented,
+	"put":    skipReasonPutNotImplemented,
+	"delete": skipReasonDeleteNotImplemented,
+}
+
+// manifestFile describes scenarios in YAML or JSON
+type manifestFile struct {
+	Name      string             `json:"name"`
+	Version   string             `json:"version"`
+	Scenarios []manifestScenario `json:"scenarios"`
+}
+
+type manifestScenario struct {
+	Id        string             `json:"id"`
+	Name      string             `json:"name"`
+	Spec      string             `json:"spec"`
+	Requires  []string           `json:"requires"`
+	TestCases []manifestTestCase `json:"test_cases"`
+	// Teardown deletes the software client registered by the scenario, if any
+	Teardown bool `json:"teardown"`
+}
+
+type manifestTestCase struct {
+	Name  string         `json:"name"`
+	Steps []manifestStep `json:"steps"`
+}
+
+type manifestStep struct {
+	Step string `json:"step"`
+	// Claims override the claims of register and update requests
+	Claims *manifestClaims `json:"claims"`
+	// RegistrationAccessToken replaces the token of the registered client in retrieve requests
+	RegistrationAccessToken string `json:"registration_access_token"`
+	Status                  int    `json:"status"`
+	ContentType             string `json:"content_type"`
+	Error                   string `json:"error"`
+	ErrorDescription        string `json:"error_description"`
+}
+
+// manifestClaims are overrides of the registration request, unset fields keep the config values
+type manifestClaims struct {
+	// Expiration is the lifetime of the request JWT, e.g. `-1h` for an expired request
+	Expiration              string   `json:"exp"`
+	Issuer                  *string  `json:"iss"`
+	Audience                *string  `json:"aud"`
+	RedirectURIs            []string `json:"redirect_uris"`
+	ResponseTypes           []string `json:"response_types"`
+	TokenEndpointAuthMethod string   `json:"token_endpoint_auth_method"`
+	SoftwareStatement       *string  `json:"software_statement"`
+	// Alg signs the request JWT
+	Alg string `json:"alg"`
+	// SignWithUnknownKey signs the request JWT with a random key instead of the config private key
+	SignWithUnknownKey bool `json:"sign_with_unknown_key"`
+}
+
+// LoadManifestFile reads a manifest file, see ParseManifest
+func LoadManifestFile(path string, cfg DCR32Config) (Manifest, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "loading manifest file")
+	}
+	return ParseManifest(content, cfg)
+}
+
+// ParseManifest builds the scenarios described in YAML or JSON, steps run against the ASPSP of the config
+func ParseManifest(content []byte, cfg DCR32Config) (Manifest, error) {
+	// json is valid yaml, decoding yaml to json allows rejecting unknown fields of both
+	var document interface{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, errors.Wrap(err, "parsing manifest file")
+	}
+	jsonContent, err := json.Marshal(document)
+	if err != nil {
+		return nil, errors.Wrap(err, "parsing manifest file")
+	}
+	var file manifestFile
+	decoder := json.NewDecoder(bytes.NewReader(jsonContent))
+	decoder.DisallowUnknownFields()
+	if err = decoder.Decode(&file); err != nil {
+		return nil, errors.Wrap(err, "parsing manifest file")
+	}
+
+	if file.Name == "" || len(file.Scenarios) == 0 {
+		return nil, errors.New("parsing manifest file: name and at least one scenario are required")
+	}
+	scenarios := Scenarios{}
+	for _, definition := range file.Scenarios {
+		scenario, buildErr := definition.build(cfg)
+		if buildErr != nil {
+			return nil, errors.Wrapf(buildErr, "parsing manifest file: scenario %q", definition.Id)
+		}
+		scenarios = append(scenarios, scenario)
+	}
+	return NewManifest(file.Name, file.Version, scenarios)
+}
+
+func (s manifestScenario) build(cfg DCR32Config) (Scenario, error) {
+	if s.Id == "" || s.Name == "" || len(s.TestCases) == 0 {
+		return nil, errors.New("id, name and at least one test case are required")
+	}
+	builder := NewBuilder(s.Id, s.Name, s.Spec)
+
+	implemented := map[string]bool{
+		"get":    cfg.GetImplemented,
+		"put":    cfg.PutImplemented,
+		"delete": cfg.DeleteImplemented,
+	}
+	for _, method := range s.Requires {
+		skipReason, ok := manifestSkipReasons[method]
+		if !ok {
+			return nil, fmt.Errorf("unknown required method %q", method)
+		}
+		if !implemented[method] {
+			builder.Skip(skipReason)
+		}
+	}
+
+	for _, definition := range s.TestCases {
+		testCase, err := definition.build(cfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "test case %q", definition.Name)
+		}
+		builder.TestCase(testCase)
+	}
+	if s.Teardown {
+		builder.Teardown(DCR32TeardownSoftwareClientTestCase(cfg, cfg.SecureClient))
+	}
+	return builder.Build(), nil
+}
+
+func (tc manifestTestCase) build(cfg DCR32Config) (TestCase, error) {
+	if tc.Name == "" || len(tc.Steps) == 0 {
+		return nil, errors.New("name and at least one step are required")
+	}
+	builder := NewTestCaseBuilder(tc.Name).
+		WithHttpClient(cfg.SecureClient).
+		WithLedger(cfg.Ledger)
+	for key, definition := range tc.Steps {
+		if err := definition.add(builder, cfg); err != nil {
+			return nil, errors.Wrapf(err, "step %d", key+1)
+		}
+	}
+	return builder.Build(), nil
+}
+
+// add appends the steps of the definition to the test case
+func (s manifestStep) add(builder *testCaseBuilder, cfg DCR32Config) error {
+	if s.Claims != nil && s.Step != manifestStepRegister && s.Step != manifestStepUpdate {
+		return errors.New("claims are only allowed in register and update steps")
+	}
+
+	registrationEndpoint := cfg.OpenIDConfig.RegistrationEndpointAsString()
+	switch s.Step {
+	case manifestStepRegister, manifestStepUpdate:
+		authoriserBuilder, err := s.Claims.apply(cfg.AuthoriserBuilder, cfg.PrivateKey)
+		if err != nil {
+			return err
+		}
+		if s.Step == manifestStepUpdate {
+			builder.GenerateSignedClaimsForRegistrationUpdate(authoriserBuilder).ClientUpdate(registrationEndpoint)
+		} else {
+			builder.GenerateSignedClaims(authoriserBuilder).PostClientRegister(registrationEndpoint)
+		}
+		builder.OutputTransactionId()
+		if s.Step == manifestStepRegister {
+			builder.CaptureRegisteredClient(authoriserBuilder)
+		}
+	case manifestStepParseClient:
+		builder.ParseClientRegisterResponse(cfg.AuthoriserBuilder)
+	case manifestStepRetrieve:
+		if s.RegistrationAccessToken != "" {
+			builder.ClientRetrieveWithRegistrationAccessToken(registrationEndpoint, s.RegistrationAccessToken)
+		} else {
+			builder.ClientRetrieve(registrationEndpoint)
+		}
+	case manifestStepDelete:
+		builder.ClientDelete(registrationEndpoint)
+	case manifestStepCredentialsGrant:
+		builder.GetClientCredentialsGrant(cfg.OpenIDConfig.TokenEndpoint)
+	case manifestStepAssertStatus:
+		if s.Status == 0 {
+			return errors.New("assert_status requires a status")
+		}
+		builder.AssertStatusCode(s.Status)
+	case manifestStepAssertContentType:
+		if s.ContentType == "" {
+			return errors.New("assert_content_type requires a content_type")
+		}
+		builder.AssertContentType(s.ContentType)
+	case manifestStepAssertErrorMessage:
+		if s.Error == "" {
+			return errors.New("assert_error_message requires an error")
+		}
+		builder.AssertErrorMessage(s.Error, s.ErrorDescription)
+	case manifestStepSchemaValidate:
+		builder.AssertValidSchemaResponse(cfg.SchemaValidator)
+	default:
+		return fmt.Errorf("unknown step %q", s.Step)
+	}
+	return nil
+}
+
+// apply overrides the claims signed by the authoriser builder
+func (c *manifestClaims) apply(
+	authoriserBuilder auth.AuthoriserBuilder,
+	privateKey crypto.Signer,
+) (auth.AuthoriserBuilder, error) {
+	if c == nil {
+		return authoriserBuilder, nil
+	}
+	if c.Expiration != "" {
+		expiration, err := time.ParseDuration(c.Expiration)
+		if err != nil {
+			return authoriserBuilder, errors.Wrap(err, "claims exp")
+		}
+		authoriserBuilder = authoriserBuilder.WithJwtExpiration(expiration)
+	}
+	if c.Issuer != nil {
+		authoriserBuilder = authoriserBuilder.WithIssuer(*c.Issuer)
+	}
+	if c.Audience != nil {
+		authoriserBuilder = authoriserBuilder.WithAud(*c.Audience)
+	}
+	if c.RedirectURIs != nil {
+		authoriserBuilder = authoriserBuilder.WithRedirectURIs(c.RedirectURIs)
+	}
+	if c.ResponseTypes != nil {
+		authoriserBuilder = authoriserBuilder.WithResponseTypes(c.ResponseTypes)
+	}
+	if c.TokenEndpointAuthMethod != "" {
+		authoriserBuilder = authoriserBuilder.WithPreferredTokenEndpointAuthMethod(c.TokenEndpointAuthMethod)
+	}
+	if c.SoftwareStatement != nil {
+		authoriserBuilder = authoriserBuilder.WithSSA(*c.SoftwareStatement)
+	}
+	if c.Alg != "" {
+		method := jwt.GetSigningMethod(c.Alg)
+		if method == nil {
+			return authoriserBuilder, fmt.Errorf("claims alg %q is not supported", c.Alg)
+		}
+		authoriserBuilder = authoriserBuilder.WithTokenEndpointAuthMethod(method)
+	}
+	if c.SignWithUnknownKey {
+		unknownKey, err := generatePrivateKeyLike(privateKey)
+		if err != nil {
+			return authoriserBuilder, errors.Wrap(err, "claims sign_with_unknown_key")
+		}
+		authoriserBuilder = authoriserBuilder.WithPrivateKey(unknownKey)
+	}
+	return authoriserBuilder, nil
+}
@@ -0,0 +1,186 @@
+package compliant
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadManifestFile(t *testing.T) {
+	cfg := DCR32Config{
+		SecureClient:      &http.Client{},
+		AuthoriserBuilder: auth.NewAuthoriserBuilder(),
+		GetImplemented:    true,
+		DeleteImplemented: true,
+	}
+
+	manifest, err := LoadManifestFile("testdata/manifest.yaml", cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, "Bank negative tests", manifest.Name())
+	assert.Equal(t, "1.0", manifest.Version())
+	require.Len(t, manifest.Scenarios(), 3)
+	assert.Equal(t, "BANK-001", manifest.Scenarios()[0].Id())
+	assert.Equal(t, "Registration fails with a redirect uri not in the software statement", manifest.Scenarios()[1].Name())
+	assert.Equal(t, "https://openbanking.atlassian.net/wiki/spaces/DZ/pages/1078034771", manifest.Scenarios()[0].Spec())
+}
+
+func TestLoadManifestFile_HandlesMissingFile(t *testing.T) {
+	_, err := LoadManifestFile("testdata/missing.yaml", DCR32Config{})
+
+	assert.Error(t, err)
+}
+
+func TestParseManifest_SkipsScenariosRequiringMethodsNotImplemented(t *testing.T) {
+	content := `{
+		"name": "json manifest",
+		"scenarios": [{
+			"id": "BANK-001",
+			"name": "update",
+			"requires": ["put"],
+			"test_cases": [{"name": "update", "steps": [{"step": "update"}]}]
+		}]
+	}`
+
+	manifest, err := ParseManifest([]byte(content), DCR32Config{AuthoriserBuilder: auth.NewAuthoriserBuilder()})
+	require.NoError(t, err)
+
+	require.Len(t, manifest.Scenarios(), 1)
+	assert.Equal(t, skipReasonPutNotImplemented, manifest.Scenarios()[0].(scenario).skipReason)
+}
+
+func TestParseManifest_HandlesInvalidManifests(t *testing.T) {
+	testCases := []struct {
+		name          string
+		content       string
+		expectedError string
+	}{
+		{
+			name:          "not yaml",
+			content:       "name: [",
+			expectedError: "parsing manifest file: yaml: line 1: did not find expected node content",
+		},
+		{
+			name:          "unknown field",
+			content:       "name: bank\nscenario: []",
+			expectedError: `parsing manifest file: json: unknown field "scenario"`,
+		},
+		{
+			name:          "no scenarios",
+			content:       "name: bank",
+			expectedError: "parsing manifest file: name and at least one scenario are required",
+		},
+		{
+			name: "unknown step",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    test_cases:
+      - name: test case
+        steps:
+          - step: register
+          - step: reboot`,
+			expectedError: `parsing manifest file: scenario "BANK-001": test case "test case": step 2: unknown step "reboot"`,
+		},
+		{
+			name: "invalid expiration",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    test_cases:
+      - name: test case
+        steps:
+          - step: register
+            claims: {exp: yesterday}`,
+			expectedError: `parsing manifest file: scenario "BANK-001": test case "test case": step 1: ` +
+				`claims exp: time: invalid duration "yesterday"`,
+		},
+		{
+			name: "unknown alg",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    test_cases:
+      - name: test case
+        steps:
+          - step: register
+            claims: {alg: XS256}`,
+			expectedError: `parsing manifest file: scenario "BANK-001": test case "test case": step 1: ` +
+				`claims alg "XS256" is not supported`,
+		},
+		{
+			name: "claims in retrieve",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    test_cases:
+      - name: test case
+        steps:
+          - step: retrieve
+            claims: {iss: foo}`,
+			expectedError: `parsing manifest file: scenario "BANK-001": test case "test case": step 1: ` +
+				`claims are only allowed in register and update steps`,
+		},
+		{
+			name: "assert status without status",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    test_cases:
+      - name: test case
+        steps:
+          - step: assert_status`,
+			expectedError: `parsing manifest file: scenario "BANK-001": test case "test case": step 1: ` +
+				`assert_status requires a status`,
+		},
+		{
+			name: "unknown required method",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    requires: [patch]
+    test_cases:
+      - name: test case
+        steps:
+          - step: delete`,
+			expectedError: `parsing manifest file: scenario "BANK-001": unknown required method "patch"`,
+		},
+		{
+			name: "duplicated scenario ids",
+			content: `
+name: bank
+scenarios:
+  - id: BANK-001
+    name: scenario
+    test_cases: [{name: test case, steps: [{step: delete}]}]
+  - id: BANK-001
+    name: another scenario
+    test_cases: [{name: test case, steps: [{step: delete}]}]`,
+			expectedError: "scenario must have unique ids",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseManifest([]byte(tc.content), DCR32Config{AuthoriserBuilder: auth.NewAuthoriserBuilder()})
+
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
@@ -1,8 +1,15 @@
 package compliant
 
 import (
-	"github.com/stretchr/testify/assert"
+	"context"
+	"fmt"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNewManifest(t *testing.T) {
@@ -37,7 +44,7 @@ func TestRunReturnsOneResultPerScenario(t *testing.T) {
 	manifest, err := NewManifest("DCR", "1.0", scenarios)
 	assert.NoError(t, err)
 
-	result := manifest.Run()
+	result := manifest.Run(context.Background())
 
 	assert.Len(t, result.Results, 2)
 }
@@ -49,8 +56,40 @@ func TestRunPassOnNoFails(t *testing.T) {
 	manifest, err := NewManifest("DCR", "1.0", scenarios)
 	assert.NoError(t, err)
 
-	result := manifest.Run()
+	result := manifest.Run(context.Background())
+
+	assert.False(t, result.Fail())
+}
+
+func TestRun_AbortedWhenScenarioCutShort(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	scenarios := Scenarios{
+		NewBuilder("1", "aborted", "spec").
+			TestCase(NewTestCaseBuilder("cancelled").Step(cancelStep{cancel: cancel}).Step(passStep{}).Build()).
+			Build(),
+	}
+	manifest, err := NewManifest("DCR", "1.0", scenarios)
+	require.NoError(t, err)
+
+	result := manifest.Run(ctx)
+
+	assert.True(t, result.Aborted())
+	assert.Equal(t, "run aborted: context canceled", result.AbortReason)
+}
+
+func TestRun_NotAbortedWhenCancelledAfterScenariosCompleted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	scenarios := Scenarios{
+		NewBuilder("1", "completed", "spec").
+			TestCase(NewTestCaseBuilder("cancelled last").Step(passStep{}).Step(cancelStep{cancel: cancel}).Build()).
+			Build(),
+	}
+	manifest, err := NewManifest("DCR", "1.0", scenarios)
+	require.NoError(t, err)
+
+	result := manifest.Run(ctx)
 
+	assert.False(t, result.Aborted())
 	assert.False(t, result.Fail())
 }
 
@@ -99,3 +138,57 @@ func TestNewFilteredManifest_ErrorsOnNoTests(t *testing.T) {
 	assert.EqualError(t, err, "no tests found to run")
 	assert.Nil(t, filteredManifest)
 }
+
+// sleepStep waits before passing, it tracks how many steps run at the same time
+type sleepStep struct {
+	duration    time.Duration
+	running     *int32
+	maxParallel *int32
+}
+
+func (s sleepStep) Run(ctx step.Context) step.Result {
+	running := atomic.AddInt32(s.running, 1)
+	for {
+		max := atomic.LoadInt32(s.maxParallel)
+		if running <= max || atomic.CompareAndSwapInt32(s.maxParallel, max, running) {
+			break
+		}
+	}
+	time.Sleep(s.duration)
+	atomic.AddInt32(s.running, -1)
+	return step.NewPassResult("sleep")
+}
+
+func TestNewParallelManifest_KeepsScenarioOrder(t *testing.T) {
+	var running, maxParallel int32
+	scenarios := Scenarios{}
+	for i := 0; i < 6; i++ {
+		// earlier scenarios take longer so they finish last
+		sleep := sleepStep{duration: time.Duration(6-i) * 10 * time.Millisecond, running: &running, maxParallel: &maxParallel}
+		scenarios = append(scenarios, NewBuilder(fmt.Sprintf("%d", i), "name", "spec").
+			TestCase(NewTestCaseBuilder("sleep").Step(sleep).Build()).
+			Build())
+	}
+	manifest, err := NewManifest("DCR", "1.0", scenarios)
+	require.NoError(t, err)
+
+	manifest, err = NewParallelManifest(manifest, 3)
+	require.NoError(t, err)
+	result := manifest.Run(context.Background())
+
+	require.Len(t, result.Results, 6)
+	for i, scenarioResult := range result.Results {
+		assert.Equal(t, fmt.Sprintf("%d", i), scenarioResult.Id)
+	}
+	assert.Equal(t, "DCR", result.Name)
+	assert.Equal(t, int32(3), maxParallel)
+}
+
+func TestNewParallelManifest_ErrorsOnInvalidWorkers(t *testing.T) {
+	manifest, err := NewManifest("DCR", "1.0", Scenarios{})
+	require.NoError(t, err)
+
+	_, err = NewParallelManifest(manifest, 0)
+
+	assert.EqualError(t, err, "parallel workers must be at least 1")
+}
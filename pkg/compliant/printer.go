@@ -6,6 +6,7 @@ import (
 	"github.com/logrusorgru/aurora"
 	"io"
 	"os"
+	"time"
 )
 
 func NewPrinter(debug bool) printer {
@@ -29,37 +30,212 @@ type printer struct {
 
 func (p printer) Print(result ManifestResult) error {
 	for _, scenarioResult := range result.Results {
-		_, err := fmt.Fprintf(p.output, "=== Scenario: %s - %s\n", scenarioResult.Id, scenarioResult.Name)
+		err := p.printScenario(scenarioResult)
 		if err != nil {
 			return err
 		}
-		for _, testCasesResult := range scenarioResult.TestCaseResults {
-			_, err := fmt.Fprintf(p.output, "\tTest case: %s\n", testCasesResult.Name)
+	}
+	return p.PrintSummary(result)
+}
+
+// PrintSummary prints the latency per endpoint and how the run ended, it completes the output of PrintEvent
+func (p printer) PrintSummary(result ManifestResult) error {
+	err := p.printLatencies(result.Latencies())
+	if err != nil {
+		return err
+	}
+	if result.Aborted() {
+		_, err := fmt.Fprintf(p.output, "%s %s\n", aurora.Red("ABORTED"), result.AbortReason)
+		return err
+	}
+	return nil
+}
+
+func (p printer) printLatencies(latencies []EndpointLatency) error {
+	if len(latencies) == 0 {
+		return nil
+	}
+	_, err := fmt.Fprintln(p.output, "=== Latency per endpoint")
+	if err != nil {
+		return err
+	}
+	for _, latency := range latencies {
+		_, err = fmt.Fprintf(p.output,
+			"\t%s: count %d, min %s, mean %s, p95 %s, max %s\n",
+			latency.Endpoint,
+			latency.Count,
+			duration(latency.Min),
+			duration(latency.Mean),
+			duration(latency.P95),
+			duration(latency.Max),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func duration(d time.Duration) time.Duration {
+	return d.Round(time.Millisecond)
+}
+
+// PrintEvent prints results as they happen, it expects scenarios to run one at a time
+func (p printer) PrintEvent(event Event) error {
+	switch event.Type {
+	case EventScenarioStarted:
+		return p.printScenarioHeader(event.ScenarioId, event.ScenarioName)
+	case EventScenarioFinished:
+		if event.ScenarioResult.SkipReason != "" {
+			err := p.printSkipReason("\t", event.ScenarioResult.SkipReason)
+			if err != nil {
+				return err
+			}
+		}
+		return p.printScenarioFooter(event.ScenarioResult)
+	case EventTestCaseStarted:
+		return p.printTestCaseHeader(testCaseLabel(event.Teardown), event.TestCase)
+	case EventTestCaseFinished:
+		if event.TestCaseResult.SkipReason != "" {
+			err := p.printSkipReason("\t\t", event.TestCaseResult.SkipReason)
+			if err != nil {
+				return err
+			}
+		}
+		return p.printTestCaseFooter(testCaseLabel(event.Teardown), event.TestCaseResult)
+	case EventStepFinished:
+		return p.printColourTestResult(event.StepResult)
+	}
+	return nil
+}
+
+// PrintFinishedScenario prints each scenario once it has finished, so output of scenarios
+// running concurrently is not interleaved
+func (p printer) PrintFinishedScenario(event Event) error {
+	if event.Type != EventScenarioFinished {
+		return nil
+	}
+	return p.printScenario(event.ScenarioResult)
+}
+
+func testCaseLabel(teardown bool) string {
+	if teardown {
+		return "Teardown"
+	}
+	return "Test case"
+}
+
+func (p printer) printScenario(scenarioResult ScenarioResult) error {
+	err := p.printScenarioHeader(scenarioResult.Id, scenarioResult.Name)
+	if err != nil {
+		return err
+	}
+	if scenarioResult.SkipReason != "" {
+		err = p.printSkipReason("\t", scenarioResult.SkipReason)
+		if err != nil {
+			return err
+		}
+	}
+	err = p.printTestCases(testCaseLabel(false), scenarioResult.TestCaseResults)
+	if err != nil {
+		return err
+	}
+	err = p.printTestCases(testCaseLabel(true), scenarioResult.TeardownResults)
+	if err != nil {
+		return err
+	}
+	return p.printScenarioFooter(scenarioResult)
+}
+
+func (p printer) printScenarioHeader(id, name string) error {
+	_, err := fmt.Fprintf(p.output, "=== Scenario: %s - %s\n", id, name)
+	return err
+}
+
+func (p printer) printScenarioFooter(result ScenarioResult) error {
+	_, err := fmt.Fprintf(p.output,
+		"=== Scenario finished: %s - %s (%s)\n",
+		result.Id,
+		result.Name,
+		duration(result.Duration()),
+	)
+	return err
+}
+
+func (p printer) printTestCaseHeader(label, name string) error {
+	_, err := fmt.Fprintf(p.output, "\t%s: %s\n", label, name)
+	return err
+}
+
+func (p printer) printTestCaseFooter(label string, result TestCaseResult) error {
+	_, err := fmt.Fprintf(p.output, "\t%s finished: %s (%s)\n", label, result.Name, duration(result.Duration()))
+	return err
+}
+
+func (p printer) printTestCases(label string, results TestCaseResults) error {
+	for _, testCasesResult := range results {
+		err := p.printTestCaseHeader(label, testCasesResult.Name)
+		if err != nil {
+			return err
+		}
+		if testCasesResult.SkipReason != "" {
+			err = p.printSkipReason("\t\t", testCasesResult.SkipReason)
 			if err != nil {
 				return err
 			}
-			for _, stepResult := range testCasesResult.Results {
-				err := p.printColourTestResult(stepResult)
-				if err != nil {
-					return err
-				}
+		}
+		for _, stepResult := range testCasesResult.Results {
+			err := p.printColourTestResult(stepResult)
+			if err != nil {
+				return err
 			}
 		}
+		err = p.printTestCaseFooter(label, testCasesResult)
+		if err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+func (p printer) printSkipReason(indent, reason string) error {
+	_, err := fmt.Fprintf(p.output, "%s%s %s\n", indent, aurora.Yellow("SKIP"), reason)
+	return err
+}
+
 func (p printer) printColourTestResult(result step.Result) error {
-	if result.Pass {
-		_, err := fmt.Fprintf(p.output, "\t\t%s %s\n", aurora.Green("PASS"), result.Name)
+	switch result.Status() {
+	case step.StatusPass:
+		_, err := fmt.Fprintf(p.output, "\t\t%s %s (%s)\n", aurora.Green("PASS"), result.Name, duration(result.Duration()))
 		if err != nil {
 			return err
 		}
-	} else {
+	case step.StatusSkip:
 		_, err := fmt.Fprintf(p.output,
 			"\t\t%s %s: %s\n",
+			aurora.Yellow("SKIP"),
+			result.Name,
+			result.SkipReason,
+		)
+		if err != nil {
+			return err
+		}
+	case step.StatusBlocked:
+		_, err := fmt.Fprintf(p.output,
+			"\t\t%s %s: blocked by %s\n",
+			aurora.Magenta("BLOCKED"),
+			result.Name,
+			result.BlockedBy,
+		)
+		if err != nil {
+			return err
+		}
+	default:
+		_, err := fmt.Fprintf(p.output,
+			"\t\t%s %s (%s): %s\n",
 			aurora.Red("FAIL"),
 			result.Name,
+			duration(result.Duration()),
 			result.FailReason,
 		)
 		if err != nil {
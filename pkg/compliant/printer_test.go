@@ -9,6 +9,7 @@ import (
 	"io/ioutil"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 // nolint:gochecknoglobals
@@ -18,17 +19,27 @@ func TestNewPrinter(t *testing.T) {
 	result := ManifestResult{
 		Results: []ScenarioResult{
 			{
-				Id:   "1",
-				Name: "scenario one",
-				Spec: "spec link",
+				Id:     "1",
+				Name:   "scenario one",
+				Spec:   "spec link",
+				Timing: timing(380 * time.Millisecond),
 				TestCaseResults: TestCaseResults{
 					{
-						Name: "tc one",
+						Name:   "tc one",
+						Timing: timing(255 * time.Millisecond),
 						Results: []step.Result{
 							{
 								Name:       "step one",
 								Pass:       false,
 								FailReason: "reasons",
+								Timing:     timing(250 * time.Millisecond),
+								Requests: []step.RequestTiming{
+									{
+										Endpoint:   step.EndpointRegister,
+										StatusCode: 400,
+										Timing:     timing(240 * time.Millisecond),
+									},
+								},
 								Debug: step.DebugMessages{
 									Item: []step.DebugMessage{
 										{
@@ -37,9 +48,51 @@ func TestNewPrinter(t *testing.T) {
 									},
 								},
 							},
+							{
+								Name:      "step two",
+								Blocked:   true,
+								BlockedBy: "tc one: step one",
+							},
 						},
 					},
 				},
+				TeardownResults: TestCaseResults{
+					{
+						Name:   "teardown",
+						Timing: timing(125 * time.Millisecond),
+						Results: []step.Result{
+							{
+								Name:   "delete client",
+								Pass:   true,
+								Timing: timing(120 * time.Millisecond),
+								Requests: []step.RequestTiming{
+									{
+										Endpoint:   step.EndpointDelete,
+										StatusCode: 204,
+										Timing:     timing(110 * time.Millisecond),
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			{
+				Id:         "2",
+				Name:       "scenario two",
+				Spec:       "spec link",
+				SkipReason: "endpoint not implemented",
+			},
+			{
+				Id:   "3",
+				Name: "scenario three",
+				Spec: "spec link",
+				TestCaseResults: TestCaseResults{
+					{
+						Name:       "tc skipped",
+						SkipReason: "endpoint not implemented",
+					},
+				},
 			},
 		},
 		Name:    "manifest test test result",
@@ -64,3 +117,20 @@ func TestNewPrinter(t *testing.T) {
 
 	assert.Equal(t, g, w.Bytes())
 }
+
+func TestPrinter_PrintsAbortReason(t *testing.T) {
+	buf := &bytes.Buffer{}
+	printer := NewPrinterWithOptions(false, buf)
+
+	err := printer.Print(ManifestResult{AbortReason: "run aborted: context canceled"})
+
+	require.NoError(t, err)
+	assert.Contains(t, buf.String(), "run aborted: context canceled")
+	assert.Contains(t, buf.String(), "ABORTED")
+}
+
+// timing is a fixed timing lasting `d` so golden files are stable
+func timing(d time.Duration) step.Timing {
+	start := time.Date(2020, 1, 1, 12, 0, 0, 0, time.UTC)
+	return step.Timing{Start: start, End: start.Add(d)}
+}
@@ -0,0 +1,32 @@
+package compliant
+
+import (
+	"context"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+type redactionDisabledCtxKey struct{}
+
+// WithoutRedaction returns a context that keeps credentials in step debug messages and fail reasons,
+// by default they are masked as soon as a step finishes so they never reach listeners or reports
+func WithoutRedaction(ctx context.Context) context.Context {
+	return context.WithValue(ctx, redactionDisabledCtxKey{}, true)
+}
+
+func redactionDisabled(ctx context.Context) bool {
+	disabled, ok := ctx.Value(redactionDisabledCtxKey{}).(bool)
+	return ok && disabled
+}
+
+func redactResult(redactor http.Redactor, result step.Result) step.Result {
+	result.FailReason = redactor.Redact(result.FailReason)
+	items := make([]step.DebugMessage, len(result.Debug.Item))
+	for i, item := range result.Debug.Item {
+		item.Message = redactor.Redact(item.Message)
+		items[i] = item
+	}
+	result.Debug.Item = items
+	return result
+}
@@ -0,0 +1,60 @@
+package compliant
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+const reportZipName = "report.zip"
+
+func NewDirReporter(config RunConfig, debug bool, dir string) dirReporter {
+	return dirReporter{
+		reporter: reporter{
+			debug:  debug,
+			config: config,
+		},
+		dir: dir,
+	}
+}
+
+// dirReporter writes the report files and their zip to a directory, unlike reporter it doesn't
+// need anyone to download the report so it suits headless runs
+type dirReporter struct {
+	reporter reporter
+	dir      string
+}
+
+// WithSigner adds a signature of the report files to the report
+func (r dirReporter) WithSigner(signer ReportSigner) dirReporter {
+	r.reporter = r.reporter.WithSigner(signer)
+	return r
+}
+
+func (r dirReporter) Report(result ManifestResult) error {
+	files, err := r.reporter.Files(result)
+	if err != nil {
+		return errors.Wrap(err, "writing report to directory")
+	}
+
+	err = os.MkdirAll(r.dir, 0755)
+	if err != nil {
+		return errors.Wrap(err, "writing report to directory")
+	}
+
+	for _, file := range files {
+		err = ioutil.WriteFile(filepath.Join(r.dir, file.Name), []byte(file.Body), 0644)
+		if err != nil {
+			return errors.Wrap(err, "writing report to directory")
+		}
+	}
+
+	zipped, err := ZipReportFiles(files)
+	if err != nil {
+		return errors.Wrap(err, "writing report to directory")
+	}
+	err = ioutil.WriteFile(filepath.Join(r.dir, reportZipName), zipped.Bytes(), 0644)
+	return errors.Wrap(err, "writing report to directory")
+}
@@ -0,0 +1,56 @@
+package compliant
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirReporter_Report(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	reportDir := filepath.Join(dir, "nested")
+	reporter := NewDirReporter(RunConfig{Brand: "brand"}, true, reportDir)
+
+	err = reporter.Report(ManifestResult{Name: "manifest", Version: "1.0"})
+	require.NoError(t, err)
+
+	for _, name := range []string{"report.json", "report.html", "debug.json", "config.json", "report.zip"} {
+		assert.FileExists(t, filepath.Join(reportDir, name))
+	}
+
+	content, err := ioutil.ReadFile(filepath.Join(reportDir, "report.json"))
+	require.NoError(t, err)
+	var report Report
+	require.NoError(t, json.Unmarshal(content, &report))
+	assert.Equal(t, "manifest", report.Name)
+
+	zipReader, err := zip.OpenReader(filepath.Join(reportDir, "report.zip"))
+	require.NoError(t, err)
+	defer zipReader.Close()
+	var zipped []string
+	for _, f := range zipReader.File {
+		zipped = append(zipped, f.Name)
+	}
+	assert.Equal(t, []string{"report.json", "report.html", "debug.json", "config.json"}, zipped)
+}
+
+func TestDirReporter_Report_WithoutDebug(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	err = NewDirReporter(RunConfig{}, false, dir).Report(ManifestResult{})
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "debug.json"))
+	assert.True(t, os.IsNotExist(err))
+	assert.FileExists(t, filepath.Join(dir, "report.json"))
+}
@@ -6,7 +6,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
@@ -35,23 +37,54 @@ type reporter struct {
 	doneSignalChan chan<- bool
 	serverAddr     string
 	config         RunConfig
+	signer         *ReportSigner
+}
+
+// WithSigner adds a signature of the report files to the report
+func (r reporter) WithSigner(signer ReportSigner) reporter {
+	r.signer = &signer
+	return r
 }
 
 // Report marshals the result and debug into json, zips them, then starts a server to host the generated zip file.
 func (r reporter) Report(result ManifestResult) error {
-	reportJson, err := json.MarshalIndent(r.mapToReport(result), "", " ")
+	files, err := r.Files(result)
+	if err != nil {
+		return err
+	}
+
+	b, err := ZipReportFiles(files)
 	if err != nil {
 		return err
 	}
+
+	r.startServer(b)
+
+	return nil
+}
+
+// Files returns the report, the debug log when enabled and the run config as json files,
+// followed by their signature when the reporter has a signer
+func (r reporter) Files(result ManifestResult) ([]ReportFile, error) {
+	reportJson, err := json.MarshalIndent(r.mapToReport(result), "", " ")
+	if err != nil {
+		return nil, err
+	}
+	html := &bytes.Buffer{}
+	if err = r.writeHTML(html, result); err != nil {
+		return nil, err
+	}
+
 	var files = []ReportFile{
 		{"report.json", string(reportJson)},
+		{"report.html", html.String()},
 	}
 
 	if r.debug {
 		var debugJson []byte
 		debugJson, err = json.MarshalIndent(r.GetDebugLog(result), "", " ")
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		files = append(files, ReportFile{"debug.json", string(debugJson)})
@@ -59,18 +92,19 @@ func (r reporter) Report(result ManifestResult) error {
 
 	config, err := json.MarshalIndent(r.config, "", " ")
 	if err != nil {
-		return err
+		return nil, err
 	}
 	files = append(files, ReportFile{"config.json", string(config)})
 
-	b, err := ZipReportFiles(files)
-	if err != nil {
-		return err
+	if r.signer != nil {
+		signature, err := r.signer.Sign(result, files)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, signature)
 	}
 
-	r.startServer(b)
-
-	return nil
+	return files, nil
 }
 
 func (r reporter) startServer(report io.Reader) {
@@ -99,27 +133,29 @@ func (r reporter) GetDebugLog(result ManifestResult) []DebugLine {
 	var log []DebugLine
 
 	for _, scenario := range result.Results {
-		for _, testcase := range scenario.TestCaseResults {
+		testcases := append(TestCaseResults{}, scenario.TestCaseResults...)
+		testcases = append(testcases, scenario.TeardownResults...)
+		for _, testcase := range testcases {
 			for _, result := range testcase.Results {
 				for _, message := range result.Debug.Item {
 					log = append(log, DebugLine{
 						Message: message.Message,
 						Time:    message.Time.Format(time.RFC3339),
 						Scenario: ReportScenario{
-							Id:   scenario.Id,
-							Name: scenario.Name,
-							Spec: scenario.Spec,
-							Pass: !scenario.Fail(),
+							Id:         scenario.Id,
+							Name:       scenario.Name,
+							Spec:       scenario.Spec,
+							Pass:       scenario.Status() == step.StatusPass,
+							Status:     scenario.Status(),
+							SkipReason: scenario.SkipReason,
 						},
 						Testcase: ReportTestcase{
-							Name: testcase.Name,
-							Pass: !testcase.Fail(),
-						},
-						Result: ReportStep{
-							Name:   result.Name,
-							Pass:   result.Pass,
-							Reason: result.FailReason,
+							Name:       testcase.Name,
+							Pass:       testcase.Status() == step.StatusPass,
+							Status:     testcase.Status(),
+							SkipReason: testcase.SkipReason,
 						},
+						Result: r.mapStepToReport(result),
 					})
 				}
 			}
@@ -132,18 +168,26 @@ func (r reporter) mapToReport(result ManifestResult) Report {
 	results := make([]ReportScenario, len(result.Results))
 	for key, scenario := range result.Results {
 		results[key] = ReportScenario{
-			Id:        scenario.Id,
-			Name:      scenario.Name,
-			Spec:      scenario.Spec,
-			Pass:      !scenario.Fail(),
-			TestCases: r.mapTCSToReport(scenario.TestCaseResults),
+			Id:           scenario.Id,
+			Name:         scenario.Name,
+			Spec:         scenario.Spec,
+			Pass:         scenario.Status() == step.StatusPass,
+			Status:       scenario.Status(),
+			SkipReason:   scenario.SkipReason,
+			TestCases:    r.mapTCSToReport(scenario.TestCaseResults),
+			Teardown:     r.mapTCSToReport(scenario.TeardownResults),
+			ReportTiming: mapTimingToReport(scenario.Timing),
 		}
 	}
 	return Report{
-		Name:      result.Name,
-		Version:   result.Version,
-		Pass:      !result.Fail(),
-		Scenarios: results,
+		Name:         result.Name,
+		Version:      result.Version,
+		Pass:         !result.Fail() && !result.Aborted(),
+		TeardownPass: !result.TeardownFail(),
+		Aborted:      result.Aborted(),
+		AbortReason:  result.AbortReason,
+		Scenarios:    results,
+		Latency:      mapLatencyToReport(result.Latencies()),
 	}
 }
 
@@ -151,9 +195,12 @@ func (r reporter) mapTCSToReport(results TestCaseResults) []ReportTestcase {
 	reportResults := make([]ReportTestcase, len(results))
 	for key, result := range results {
 		reportResults[key] = ReportTestcase{
-			Name:  result.Name,
-			Pass:  !result.Fail(),
-			Steps: r.mapStepsToReport(result.Results),
+			Name:         result.Name,
+			Pass:         result.Status() == step.StatusPass,
+			Status:       result.Status(),
+			SkipReason:   result.SkipReason,
+			Steps:        r.mapStepsToReport(result.Results),
+			ReportTiming: mapTimingToReport(result.Timing),
 		}
 	}
 	return reportResults
@@ -162,41 +209,136 @@ func (r reporter) mapTCSToReport(results TestCaseResults) []ReportTestcase {
 func (r reporter) mapStepsToReport(results step.Results) []ReportStep {
 	stepResults := make([]ReportStep, len(results))
 	for key, result := range results {
-		stepResults[key] = ReportStep{
-			Name:   result.Name,
-			Pass:   result.Pass,
-			Reason: result.FailReason,
-		}
+		stepResults[key] = r.mapStepToReport(result)
 	}
 	return stepResults
 }
 
+func (r reporter) mapStepToReport(result step.Result) ReportStep {
+	return ReportStep{
+		Name:         result.Name,
+		Pass:         result.Status() == step.StatusPass,
+		Status:       result.Status(),
+		Reason:       result.FailReason,
+		SkipReason:   result.SkipReason,
+		BlockedBy:    result.BlockedBy,
+		ReportTiming: mapTimingToReport(result.Timing),
+		Requests:     mapRequestsToReport(result.Requests),
+	}
+}
+
+func mapRequestsToReport(requests []step.RequestTiming) []ReportRequest {
+	reportRequests := make([]ReportRequest, len(requests))
+	for key, request := range requests {
+		reportRequests[key] = ReportRequest{
+			Endpoint:   request.Endpoint,
+			StatusCode: request.StatusCode,
+			DurationMs: milliseconds(request.Duration()),
+		}
+	}
+	return reportRequests
+}
+
+func mapLatencyToReport(latencies []EndpointLatency) []ReportLatency {
+	reportLatencies := make([]ReportLatency, len(latencies))
+	for key, latency := range latencies {
+		reportLatencies[key] = ReportLatency{
+			Endpoint: latency.Endpoint,
+			Count:    latency.Count,
+			MinMs:    milliseconds(latency.Min),
+			MeanMs:   milliseconds(latency.Mean),
+			P95Ms:    milliseconds(latency.P95),
+			MaxMs:    milliseconds(latency.Max),
+		}
+	}
+	return reportLatencies
+}
+
+func mapTimingToReport(timing step.Timing) ReportTiming {
+	return ReportTiming{
+		StartedAt:  reportTime(timing.Start),
+		FinishedAt: reportTime(timing.End),
+		DurationMs: milliseconds(timing.Duration()),
+	}
+}
+
+// reportTime formats a time for the report, an unknown time is left empty
+func reportTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// milliseconds with microsecond precision
+func milliseconds(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
 type Report struct {
-	Name      string           `json:"name"`
-	Version   string           `json:"version"`
-	Pass      bool             `json:"pass"`
-	Scenarios []ReportScenario `json:"scenarios,omitempty"`
+	Name         string           `json:"name"`
+	Version      string           `json:"version"`
+	Pass         bool             `json:"pass"`
+	TeardownPass bool             `json:"teardown_pass"`
+	Aborted      bool             `json:"aborted"`
+	AbortReason  string           `json:"abort_reason,omitempty"`
+	Scenarios    []ReportScenario `json:"scenarios,omitempty"`
+	Latency      []ReportLatency  `json:"latency,omitempty"`
+}
+
+// ReportTiming is inlined in scenarios, test cases and steps
+type ReportTiming struct {
+	StartedAt  string  `json:"started_at,omitempty"`
+	FinishedAt string  `json:"finished_at,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+type ReportLatency struct {
+	Endpoint string  `json:"endpoint"`
+	Count    int     `json:"count"`
+	MinMs    float64 `json:"min_ms"`
+	MeanMs   float64 `json:"mean_ms"`
+	P95Ms    float64 `json:"p95_ms"`
+	MaxMs    float64 `json:"max_ms"`
+}
+
+type ReportRequest struct {
+	Endpoint   string  `json:"endpoint"`
+	StatusCode int     `json:"status_code,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
 }
 
 type ReportScenario struct {
-	Id        string           `json:"id"`
-	Name      string           `json:"name"`
-	Spec      string           `json:"spec"`
-	Pass      bool             `json:"pass"`
-	TestCases []ReportTestcase `json:"test_cases,omitempty"`
+	Id         string           `json:"id"`
+	Name       string           `json:"name"`
+	Spec       string           `json:"spec"`
+	Pass       bool             `json:"pass"`
+	Status     step.Status      `json:"status"`
+	SkipReason string           `json:"skip_reason,omitempty"`
+	TestCases  []ReportTestcase `json:"test_cases,omitempty"`
+	Teardown   []ReportTestcase `json:"teardown,omitempty"`
+	ReportTiming
 }
 
 type ReportTestcase struct {
-	Name  string       `json:"name"`
-	Pass  bool         `json:"pass"`
-	Steps []ReportStep `json:"steps,omitempty"`
+	Name       string       `json:"name"`
+	Pass       bool         `json:"pass"`
+	Status     step.Status  `json:"status"`
+	SkipReason string       `json:"skip_reason,omitempty"`
+	Steps      []ReportStep `json:"steps,omitempty"`
+	ReportTiming
 }
 
 type ReportStep struct {
-	Name   string   `json:"name"`
-	Pass   bool     `json:"pass"`
-	Reason string   `json:"reason,omitempty"`
-	Debug  []string `json:"debug,omitempty"`
+	Name       string      `json:"name"`
+	Pass       bool        `json:"pass"`
+	Status     step.Status `json:"status"`
+	Reason     string      `json:"reason,omitempty"`
+	SkipReason string      `json:"skip_reason,omitempty"`
+	BlockedBy  string      `json:"blocked_by,omitempty"`
+	Debug      []string    `json:"debug,omitempty"`
+	ReportTiming
+	Requests []ReportRequest `json:"requests,omitempty"`
 }
 
 type downloadHandler struct {
@@ -206,12 +348,18 @@ type downloadHandler struct {
 
 func (h downloadHandler) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
 	if r.URL.Query().Get("download") != "" {
-		_, err := io.Copy(rw, h.report)
+		b, err := ioutil.ReadAll(h.report)
 		if err != nil {
 			rw.WriteHeader(http.StatusInternalServerError)
 			fmt.Printf("Server error: %s", err.Error())
+			h.doneSignalChan <- true
+			return
 		}
 		rw.Header().Add("Content-Type", "application/zip")
+		rw.Header().Add("Content-Length", strconv.Itoa(len(b)))
+		if _, err = rw.Write(b); err != nil {
+			fmt.Printf("Server error: %s", err.Error())
+		}
 		h.doneSignalChan <- true
 		return
 	}
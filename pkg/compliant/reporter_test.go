@@ -30,6 +30,14 @@ func TestNewReporter(t *testing.T) {
 								Name:       "step one",
 								Pass:       false,
 								FailReason: "reasons",
+								Timing:     timing(250 * time.Millisecond),
+								Requests: []step.RequestTiming{
+									{
+										Endpoint:   step.EndpointRegister,
+										StatusCode: 400,
+										Timing:     timing(240 * time.Millisecond),
+									},
+								},
 								Debug: step.DebugMessages{
 									Item: []step.DebugMessage{
 										{
@@ -38,10 +46,51 @@ func TestNewReporter(t *testing.T) {
 									},
 								},
 							},
+							{
+								Name:      "step two",
+								Blocked:   true,
+								BlockedBy: "tc one: step one",
+							},
+						},
+					},
+				},
+				TeardownResults: TestCaseResults{
+					{
+						Name: "teardown",
+						Results: []step.Result{
+							{
+								Name:   "delete client",
+								Pass:   true,
+								Timing: timing(120 * time.Millisecond),
+								Requests: []step.RequestTiming{
+									{
+										Endpoint:   step.EndpointDelete,
+										StatusCode: 204,
+										Timing:     timing(110 * time.Millisecond),
+									},
+								},
+							},
 						},
 					},
 				},
 			},
+			{
+				Id:         "2",
+				Name:       "scenario two",
+				Spec:       "spec link",
+				SkipReason: "endpoint not implemented",
+			},
+			{
+				Id:   "3",
+				Name: "scenario three",
+				Spec: "spec link",
+				TestCaseResults: TestCaseResults{
+					{
+						Name:       "tc skipped",
+						SkipReason: "endpoint not implemented",
+					},
+				},
+			},
 		},
 		Name:    "manifest test test result",
 		Version: "0.0",
@@ -142,3 +191,43 @@ func Copy(src, dst string) error {
 	}
 	return out.Close()
 }
+
+func TestReporter_MapToReport_Aborted(t *testing.T) {
+	result := ManifestResult{
+		Name:        "manifest",
+		Version:     "1.0",
+		AbortReason: "run aborted: context deadline exceeded",
+	}
+
+	report := reporter{}.mapToReport(result)
+
+	assert.False(t, report.Pass)
+	assert.True(t, report.Aborted)
+	assert.Equal(t, "run aborted: context deadline exceeded", report.AbortReason)
+}
+
+func TestReporter_GetDebugLog_SkippedIsNotPass(t *testing.T) {
+	debug := step.NewDebug()
+	debug.Log("signing alg not supported")
+	skipped := step.NewSkipResult("register", "signing alg not supported")
+	skipped.Debug = *debug
+	result := ManifestResult{
+		Results: []ScenarioResult{
+			{
+				Id:   "DCR-001",
+				Name: "scenario",
+				TestCaseResults: TestCaseResults{
+					{Name: "test case", Results: step.Results{skipped}},
+				},
+			},
+		},
+	}
+
+	log := reporter{}.GetDebugLog(result)
+
+	require.Len(t, log, 1)
+	assert.Equal(t, step.StatusSkip, log[0].Scenario.Status)
+	assert.False(t, log[0].Scenario.Pass)
+	assert.Equal(t, step.StatusSkip, log[0].Testcase.Status)
+	assert.False(t, log[0].Testcase.Pass)
+}
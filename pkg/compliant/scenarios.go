@@ -1,9 +1,19 @@
 package compliant
 
-import "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+)
+
+// teardownTimeout bounds the teardown of a scenario, it is not cancelled with the run
+// so clients are still deleted when the run is aborted
+const teardownTimeout = 30 * time.Second
 
 type Scenario interface {
-	Run() ScenarioResult
+	Run(ctx context.Context) ScenarioResult
 	Id() string
 	Name() string
 	Spec() string
@@ -12,10 +22,34 @@ type Scenario interface {
 type Scenarios []Scenario
 
 type ScenarioResult struct {
-	Id   string
-	Name string
-	Spec string
+	Id         string
+	Name       string
+	Spec       string
+	SkipReason string
+	// AbortReason is set when the run was cancelled or timed out before the scenario completed
+	AbortReason string
 	TestCaseResults
+	// TeardownResults are kept apart from the test case results so cleanup doesn't affect the conformance verdict
+	TeardownResults TestCaseResults
+	step.Timing
+}
+
+// TeardownFail is true when cleaning up after the scenario failed
+func (r ScenarioResult) TeardownFail() bool {
+	return r.TeardownResults.Fail()
+}
+
+func (r ScenarioResult) Status() step.Status {
+	if r.SkipReason != "" || r.Skipped() {
+		return step.StatusSkip
+	}
+	if r.Fail() {
+		return step.StatusFail
+	}
+	if r.Blocked() {
+		return step.StatusBlocked
+	}
+	return step.StatusPass
 }
 
 type ScenariosResult []ScenarioResult
@@ -29,11 +63,22 @@ func (r ScenariosResult) Fail() bool {
 	return false
 }
 
+func (r ScenariosResult) TeardownFail() bool {
+	for _, result := range r {
+		if result.TeardownFail() {
+			return true
+		}
+	}
+	return false
+}
+
 type scenario struct {
-	id   string
-	name string
-	spec string
-	tcs  []TestCase
+	id         string
+	name       string
+	spec       string
+	skipReason string
+	tcs        []TestCase
+	teardown   []TestCase
 }
 
 func NewScenario(id, name, spec string, tcs []TestCase) Scenario {
@@ -45,6 +90,28 @@ func NewScenario(id, name, spec string, tcs []TestCase) Scenario {
 	}
 }
 
+// NewScenarioWithTeardown creates a scenario with teardown test cases that always run after the scenario
+// test cases, sharing their context, regardless of the outcome
+func NewScenarioWithTeardown(id, name, spec string, tcs, teardown []TestCase) Scenario {
+	return scenario{
+		id:       id,
+		name:     name,
+		spec:     spec,
+		tcs:      tcs,
+		teardown: teardown,
+	}
+}
+
+// NewSkippedScenario creates a scenario that doesn't run any test cases and reports as skipped with a reason
+func NewSkippedScenario(id, name, spec, reason string) Scenario {
+	return scenario{
+		id:         id,
+		name:       name,
+		spec:       spec,
+		skipReason: reason,
+	}
+}
+
 func (s scenario) Id() string {
 	return s.id
 }
@@ -57,18 +124,65 @@ func (s scenario) Spec() string {
 	return s.spec
 }
 
-func (s scenario) Run() ScenarioResult {
-	ctx := step.NewContext()
+func (s scenario) Run(ctx context.Context) ScenarioResult {
+	scope := eventScope{scenarioId: s.id, scenarioName: s.name}
+	ctx = withEventScope(ctx, scope)
+	emit(ctx, Event{Type: EventScenarioStarted})
+
+	start := time.Now()
+	result := s.run(ctx, scope)
+	result.Timing = step.NewTiming(start)
+
+	emit(ctx, Event{Type: EventScenarioFinished, ScenarioResult: result})
+	return result
+}
+
+func (s scenario) run(ctx context.Context, scope eventScope) ScenarioResult {
+	skipReason := s.skipReason
+	aborted := ""
+	if skipReason == "" && ctx.Err() != nil {
+		skipReason = abortReason(ctx.Err())
+		aborted = skipReason
+	}
+	if skipReason != "" {
+		return ScenarioResult{
+			Id:          s.id,
+			Name:        s.name,
+			Spec:        s.spec,
+			SkipReason:  skipReason,
+			AbortReason: aborted,
+		}
+	}
+
+	stepCtx := step.NewContextWithParent(ctx)
 	var results TestCaseResults
 	for _, tc := range s.tcs {
-		tcResult := tc.Run(ctx)
+		tcResult := tc.Run(stepCtx)
+		if aborted == "" {
+			aborted = tcResult.AbortReason
+		}
 		results = append(results, tcResult)
 	}
 
+	scope.teardown = true
+	teardownCtx, cancel := context.WithTimeout(withEventScope(withoutCancel(ctx), scope), teardownTimeout)
+	defer cancel()
+	var teardownResults TestCaseResults
+	for _, tc := range s.teardown {
+		teardownResults = append(teardownResults, tc.Run(stepCtx.WithContext(teardownCtx)))
+	}
+
 	return ScenarioResult{
 		Id:              s.id,
 		Name:            s.name,
 		Spec:            s.spec,
+		AbortReason:     aborted,
 		TestCaseResults: results,
+		TeardownResults: teardownResults,
 	}
 }
+
+// abortReason describes why a run stopped before all scenarios completed
+func abortReason(err error) string {
+	return fmt.Sprintf("run aborted: %v", err)
+}
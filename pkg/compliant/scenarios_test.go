@@ -1,9 +1,17 @@
 package compliant
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 func TestScenarioResult(t *testing.T) {
@@ -68,7 +76,7 @@ func TestNewScenario_RunsAllTestCases(t *testing.T) {
 	}
 	scenario := NewScenario("#1", "some scenario", "spec link", tcs)
 
-	results := scenario.Run()
+	results := scenario.Run(context.Background())
 
 	assert.Equal(t, "some scenario", scenario.Name())
 	assert.Equal(t, "spec link", scenario.Spec())
@@ -76,3 +84,114 @@ func TestNewScenario_RunsAllTestCases(t *testing.T) {
 	assert.Equal(t, "some scenario", results.Name)
 	assert.Len(t, results.TestCaseResults, 2)
 }
+
+func TestNewSkippedScenario_DoesNotRunTestCases(t *testing.T) {
+	scenario := NewBuilder("#1", "some scenario", "spec link").
+		TestCase(NewTestCaseBuilder("always fails").Step(failStep{}).Build()).
+		Skip("not implemented").
+		Build()
+
+	results := scenario.Run(context.Background())
+
+	assert.Equal(t, "some scenario", results.Name)
+	assert.Equal(t, "spec link", results.Spec)
+	assert.Equal(t, "not implemented", results.SkipReason)
+	assert.Empty(t, results.TestCaseResults)
+	assert.False(t, results.Fail())
+	assert.Equal(t, step.StatusSkip, results.Status())
+}
+
+func TestScenario_Run_TeardownAlwaysRuns(t *testing.T) {
+	scenario := NewBuilder("#1", "some scenario", "spec link").
+		TestCase(NewTestCaseBuilder("always fails").Step(failStep{}).Build()).
+		Teardown(NewTestCaseBuilder("cleanup").Step(failStep{}).Build()).
+		Build()
+
+	results := scenario.Run(context.Background())
+
+	assert.Len(t, results.TestCaseResults, 1)
+	assert.Len(t, results.TeardownResults, 1)
+	assert.Equal(t, "cleanup", results.TeardownResults[0].Name)
+	assert.True(t, results.TeardownFail())
+}
+
+func TestScenario_Run_TeardownDoesNotAffectResult(t *testing.T) {
+	scenario := NewBuilder("#1", "some scenario", "spec link").
+		TestCase(NewTestCaseBuilder("always pass").Step(passStep{}).Build()).
+		Teardown(NewTestCaseBuilder("cleanup").Step(failStep{}).Build()).
+		Build()
+
+	results := scenario.Run(context.Background())
+
+	assert.False(t, results.Fail())
+	assert.Equal(t, step.StatusPass, results.Status())
+	assert.True(t, results.TeardownFail())
+}
+
+// cancelStep aborts the run it is part of
+type cancelStep struct {
+	cancel context.CancelFunc
+}
+
+func (s cancelStep) Run(ctx step.Context) step.Result {
+	s.cancel()
+	return step.NewPassResult("cancel")
+}
+
+func TestScenario_Run_AbortedStillRunsTeardown(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	scenario := NewBuilder("#1", "some scenario", "spec link").
+		TestCase(NewTestCaseBuilder("aborted").Step(cancelStep{cancel: cancel}).Step(passStep{}).Build()).
+		TestCase(NewTestCaseBuilder("not started").Step(passStep{}).Build()).
+		Teardown(NewTestCaseBuilder("cleanup").Step(passStep{}).Build()).
+		Build()
+
+	results := scenario.Run(ctx)
+
+	assert.Equal(t, step.StatusBlocked, results.TestCaseResults[0].Results[1].Status())
+	assert.Equal(t, "run aborted: context canceled", results.TestCaseResults[0].Results[1].BlockedBy)
+	assert.Equal(t, step.StatusBlocked, results.TestCaseResults[1].Status())
+	assert.Len(t, results.TeardownResults, 1)
+	assert.Equal(t, step.StatusPass, results.TeardownResults[0].Status())
+}
+
+func TestScenario_Run_SkippedWhenRunAborted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	scenario := NewBuilder("#1", "some scenario", "spec link").
+		TestCase(NewTestCaseBuilder("never runs").Step(failStep{}).Build()).
+		Build()
+
+	results := scenario.Run(ctx)
+
+	assert.Equal(t, step.StatusSkip, results.Status())
+	assert.Equal(t, "run aborted: context canceled", results.SkipReason)
+}
+
+func TestScenario_Run_TagsRecordedRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	recorder := http2.NewHARRecorder("conformance-dcr", "test")
+	client := &http.Client{Transport: recorder.RoundTripper(nil)}
+	scenario := NewBuilder("DCR-001", "scenario", "spec").
+		TestCase(NewTestCaseBuilder("get").WithHttpClient(client).Get(server.URL).Build()).
+		Teardown(NewTestCaseBuilder("cleanup").WithHttpClient(client).Get(server.URL).Build()).
+		Build()
+
+	scenario.Run(context.Background())
+
+	out := &bytes.Buffer{}
+	require.NoError(t, recorder.Write(out))
+	var har struct {
+		Log struct {
+			Entries []map[string]interface{}
+		}
+	}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &har))
+	require.Len(t, har.Log.Entries, 2)
+	assert.Equal(t, "DCR-001", har.Log.Entries[0]["_scenarioId"])
+	assert.Equal(t, "get", har.Log.Entries[0]["_testCase"])
+	assert.Nil(t, har.Log.Entries[0]["_teardown"])
+	assert.Equal(t, "cleanup", har.Log.Entries[1]["_testCase"])
+	assert.Equal(t, true, har.Log.Entries[1]["_teardown"])
+}
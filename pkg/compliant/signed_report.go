@@ -0,0 +1,209 @@
+package compliant
+
+import (
+	"archive/zip"
+	"crypto"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+const reportSignatureName = "report.jws"
+
+// ToolVersion identifies the build of the tool that produced a report
+type ToolVersion struct {
+	Version    string `json:"version"`
+	CommitHash string `json:"commit_hash"`
+	BuildTime  string `json:"build_time"`
+}
+
+// ReportSignature are the claims of the signed report, they bind the report files to the tool build
+// and config that produced them
+type ReportSignature struct {
+	Tool         ToolVersion          `json:"tool"`
+	ConfigSha256 string               `json:"config_sha256"`
+	Pass         bool                 `json:"pass"`
+	Files        map[string]string    `json:"files"`
+	Results      []ReportResultStatus `json:"results"`
+	jwt.StandardClaims
+}
+
+// ReportResultStatus is the status of a scenario at the time the report was signed
+type ReportResultStatus struct {
+	Id     string `json:"id"`
+	Status string `json:"status"`
+}
+
+func NewReportSigner(key crypto.Signer, kid string, tool ToolVersion, config []byte) ReportSigner {
+	return ReportSigner{
+		key:    key,
+		kid:    kid,
+		tool:   tool,
+		config: config,
+	}
+}
+
+// ReportSigner signs report files as a JWS listing the sha256 digest of each file
+type ReportSigner struct {
+	key    crypto.Signer
+	kid    string
+	tool   ToolVersion
+	config []byte
+}
+
+func (s ReportSigner) Sign(result ManifestResult, files []ReportFile) (ReportFile, error) {
+	claims := ReportSignature{
+		Tool:         s.tool,
+		ConfigSha256: sha256Hex(s.config),
+		Pass:         !result.Fail() && !result.Aborted(),
+		Files:        map[string]string{},
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt: time.Now().Unix(),
+		},
+	}
+	for _, file := range files {
+		claims.Files[file.Name] = sha256Hex([]byte(file.Body))
+	}
+	for _, scenario := range result.Results {
+		claims.Results = append(claims.Results, ReportResultStatus{Id: scenario.Id, Status: string(scenario.Status())})
+	}
+
+	method, err := defaultTokenSignMethod(s.key)
+	if err != nil {
+		return ReportFile{}, errors.Wrap(err, "signing report")
+	}
+	token := jwt.NewWithClaims(method, claims)
+	if s.kid != "" {
+		token.Header["kid"] = s.kid
+	}
+	signed, err := token.SignedString(s.key)
+	if err != nil {
+		return ReportFile{}, errors.Wrap(err, "signing report")
+	}
+	return ReportFile{reportSignatureName, signed}, nil
+}
+
+// VerifyReport checks the signature of a report directory or zip and that every report file matches
+// the digest it was signed with, files added to or removed from the report fail verification
+func VerifyReport(path string, key crypto.PublicKey) (ReportSignature, error) {
+	files, err := readReportFiles(path)
+	if err != nil {
+		return ReportSignature{}, errors.Wrap(err, "verifying report")
+	}
+
+	signed, found := files[reportSignatureName]
+	if !found {
+		return ReportSignature{}, fmt.Errorf("verifying report: %s not found in %s", reportSignatureName, path)
+	}
+	delete(files, reportSignatureName)
+
+	claims := ReportSignature{}
+	_, err = jwt.ParseWithClaims(string(signed), &claims, func(token *jwt.Token) (interface{}, error) {
+		if supportedTokenSignMethod(token.Method.Alg()) == nil {
+			return nil, fmt.Errorf("unexpected signing method %s", token.Method.Alg())
+		}
+		return key, nil
+	})
+	if err != nil {
+		return ReportSignature{}, errors.Wrap(err, "verifying report signature")
+	}
+
+	var mismatches []string
+	for name, digest := range claims.Files {
+		content, ok := files[name]
+		if !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s is missing", name))
+			continue
+		}
+		if sha256Hex(content) != digest {
+			mismatches = append(mismatches, fmt.Sprintf("%s has been modified", name))
+		}
+	}
+	for name := range files {
+		if _, ok := claims.Files[name]; !ok {
+			mismatches = append(mismatches, fmt.Sprintf("%s is not signed", name))
+		}
+	}
+	if len(mismatches) > 0 {
+		sort.Strings(mismatches)
+		return claims, fmt.Errorf("verifying report digests: %s", strings.Join(mismatches, ", "))
+	}
+
+	return claims, nil
+}
+
+// readReportFiles reads the report files from a report directory or zip
+func readReportFiles(path string) (map[string][]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return readReportDir(path)
+	}
+	return readReportZip(path)
+}
+
+// readReportDir reads the files of a report directory, the zip is ignored as it holds the same files
+func readReportDir(dir string) (map[string][]byte, error) {
+	entries, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := map[string][]byte{}
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == reportZipName {
+			continue
+		}
+		var content []byte
+		content, err = ioutil.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = content
+	}
+	return files, nil
+}
+
+func readReportZip(path string) (map[string][]byte, error) {
+	zipReader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, err
+	}
+	defer zipReader.Close()
+
+	files := map[string][]byte{}
+	for _, f := range zipReader.File {
+		var content []byte
+		content, err = readZipFile(f)
+		if err != nil {
+			return nil, err
+		}
+		files[f.Name] = content
+	}
+	return files, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return ioutil.ReadAll(rc)
+}
+
+func sha256Hex(content []byte) string {
+	digest := sha256.Sum256(content)
+	return hex.EncodeToString(digest[:])
+}
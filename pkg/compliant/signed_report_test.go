@@ -0,0 +1,131 @@
+package compliant
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedReportDir(t *testing.T, key crypto.Signer) string {
+	dir, err := ioutil.TempDir("", "signed_report")
+	require.NoError(t, err)
+
+	result := ManifestResult{
+		Name:    "manifest",
+		Version: "1.0",
+		Results: []ScenarioResult{
+			{
+				Id:   "DCR-001",
+				Name: "scenario",
+				TestCaseResults: TestCaseResults{
+					{Name: "test case", Results: step.Results{{Name: "step", Pass: true}}},
+				},
+			},
+		},
+	}
+	signer := NewReportSigner(key, "kid", ToolVersion{Version: "v1.2.3", CommitHash: "abc"}, []byte(`{"brand":"b"}`))
+	err = NewDirReporter(RunConfig{Brand: "b"}, true, dir).WithSigner(signer).Report(result)
+	require.NoError(t, err)
+	return dir
+}
+
+func TestVerifyReport(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	dir := signedReportDir(t, key)
+	defer os.RemoveAll(dir)
+
+	for _, path := range []string{dir, filepath.Join(dir, "report.zip")} {
+		claims, err := VerifyReport(path, &key.PublicKey)
+
+		require.NoError(t, err)
+		assert.Equal(t, "v1.2.3", claims.Tool.Version)
+		assert.Equal(t, "abc", claims.Tool.CommitHash)
+		assert.Equal(t, sha256Hex([]byte(`{"brand":"b"}`)), claims.ConfigSha256)
+		assert.True(t, claims.Pass)
+		assert.Equal(t, []ReportResultStatus{{Id: "DCR-001", Status: "PASS"}}, claims.Results)
+		assert.Len(t, claims.Files, 4)
+	}
+}
+
+func TestVerifyReport_ECKey(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	dir := signedReportDir(t, key)
+	defer os.RemoveAll(dir)
+
+	claims, err := VerifyReport(dir, &key.PublicKey)
+
+	require.NoError(t, err)
+	assert.True(t, claims.Pass)
+}
+
+func TestVerifyReport_SignatureHeader(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	dir := signedReportDir(t, key)
+	defer os.RemoveAll(dir)
+
+	signed, err := ioutil.ReadFile(filepath.Join(dir, "report.jws"))
+	require.NoError(t, err)
+	token, _, err := new(jwt.Parser).ParseUnverified(string(signed), &ReportSignature{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "PS256", token.Header["alg"])
+	assert.Equal(t, "kid", token.Header["kid"])
+}
+
+func TestVerifyReport_FailsWithModifiedFile(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	dir := signedReportDir(t, key)
+	defer os.RemoveAll(dir)
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "report.json"), []byte(`{"pass":true}`), 0644))
+	require.NoError(t, os.Remove(filepath.Join(dir, "debug.json")))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(dir, "extra.json"), []byte(`{}`), 0644))
+
+	_, err = VerifyReport(dir, &key.PublicKey)
+
+	assert.EqualError(
+		t,
+		err,
+		"verifying report digests: debug.json is missing, extra.json is not signed, report.json has been modified",
+	)
+}
+
+func TestVerifyReport_FailsWithOtherKey(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	dir := signedReportDir(t, key)
+	defer os.RemoveAll(dir)
+
+	_, err = VerifyReport(dir, &otherKey.PublicKey)
+
+	assert.EqualError(t, err, "verifying report signature: crypto/rsa: verification error")
+}
+
+func TestVerifyReport_FailsWithoutSignature(t *testing.T) {
+	dir, err := ioutil.TempDir("", "report")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	require.NoError(t, NewDirReporter(RunConfig{}, false, dir).Report(ManifestResult{}))
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	_, err = VerifyReport(dir, &key.PublicKey)
+
+	assert.EqualError(t, err, "verifying report: report.jws not found in "+dir)
+}
@@ -53,8 +53,25 @@ func (c claims) Run(ctx Context) Result {
 		return NewFailResultWithDebug(c.stepName, err.Error(), debug)
 	}
 
+	debug.LogJWTs(signedClaims)
+
 	debug.Logf("setting signed claims in context var: %s", c.jwtClaimsCtxKey)
 	ctx.SetString(c.jwtClaimsCtxKey, signedClaims)
 
 	return NewPassResultWithDebug(c.stepName, debug)
 }
+
+func (c claims) Name() string {
+	return c.stepName
+}
+
+func (c claims) Requires() []string {
+	if c.updateRegistration {
+		return []string{c.clientCtxKey}
+	}
+	return nil
+}
+
+func (c claims) Produces() []string {
+	return []string{c.jwtClaimsCtxKey}
+}
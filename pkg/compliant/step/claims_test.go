@@ -52,7 +52,7 @@ func TestClaims_Run_FailsOnClaimsError(t *testing.T) {
 	result := step.Run(ctx)
 
 	assert.False(t, result.Pass)
-	assert.Equal(t, "no authoriser was found for openid config", result.FailReason)
+	assert.Contains(t, result.FailReason, "no token endpoint auth method supported by both the ASPSP and the tool")
 }
 
 func generateKey(t *testing.T) *rsa.PrivateKey {
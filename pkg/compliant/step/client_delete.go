@@ -34,7 +34,7 @@ func (s clientDelete) Run(ctx Context) Result {
 	}
 
 	url := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
-	req, err := http.NewRequest(http.MethodDelete, url, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
 	if err != nil {
 		return NewFailResult(s.stepName, fmt.Sprintf("unable to create request %s: %v", url, err))
 	}
@@ -46,7 +46,7 @@ func (s clientDelete) Run(ctx Context) Result {
 
 	debug.Log(http2.DebugRequest(req))
 
-	res, err := s.client.Do(req)
+	res, err := do(ctx, s.client, req, EndpointDelete)
 	if err != nil {
 		return NewFailResult(s.stepName, fmt.Sprintf("unable to call endpoint %s: %v", url, err))
 	}
@@ -59,5 +59,24 @@ func (s clientDelete) Run(ctx Context) Result {
 		return NewFailResultWithDebug(s.stepName, message, debug)
 	}
 
+	ctx.SetString(deletedClientCtxKey(s.clientCtxKey), client.Id())
 	return NewPassResultWithDebug(s.stepName, debug)
 }
+
+// deletedClientCtxKey holds the id of the software client of `clientCtxKey` once deleted, the client stays in
+// context for the steps checking it can't be used anymore but teardown doesn't delete it again
+func deletedClientCtxKey(clientCtxKey string) string {
+	return clientCtxKey + ".deleted"
+}
+
+func (s clientDelete) Name() string {
+	return s.stepName
+}
+
+func (s clientDelete) Requires() []string {
+	return []string{s.clientCtxKey}
+}
+
+func (s clientDelete) Produces() []string {
+	return nil
+}
@@ -37,6 +37,9 @@ func TestNewClientDelete(t *testing.T) {
 	assert.True(t, result.Pass)
 	assert.Equal(t, "Software client delete", result.Name)
 	assert.Equal(t, "", result.FailReason)
+	deletedId, err := ctx.GetString(deletedClientCtxKey("clientKey"))
+	assert.NoError(t, err)
+	assert.Equal(t, clientID, deletedId)
 }
 
 func TestNewClientDelete_Expects204(t *testing.T) {
@@ -35,7 +35,7 @@ func (s clientRegister) Run(ctx Context) Result {
 		return s.failResult(fmt.Sprintf("getting jwt claims: %s", err.Error()))
 	}
 
-	response, err := s.doJwtPostRequest(s.registrationEndpoint, jwtClaims)
+	response, err := s.doJwtPostRequest(ctx, s.registrationEndpoint, jwtClaims)
 	if err != nil {
 		return s.failResult(err.Error())
 	}
@@ -46,9 +46,9 @@ func (s clientRegister) Run(ctx Context) Result {
 	return NewPassResultWithDebug(s.stepName, s.debug)
 }
 
-func (s clientRegister) doJwtPostRequest(endpoint, jwtClaims string) (*http.Response, error) {
+func (s clientRegister) doJwtPostRequest(ctx Context, endpoint, jwtClaims string) (*http.Response, error) {
 	body := bytes.NewBufferString(jwtClaims)
-	req, err := http.NewRequest(http.MethodPost, endpoint, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating jose post request")
 	}
@@ -57,7 +57,7 @@ func (s clientRegister) doJwtPostRequest(endpoint, jwtClaims string) (*http.Resp
 	s.debug.Log(http2.DebugRequest(req))
 
 	s.debug.Log("making request")
-	response, err := s.client.Do(req)
+	response, err := do(ctx, s.client, req, EndpointRegister)
 	if err != nil {
 		return nil, errors.Wrap(err, "making jose post request")
 	}
@@ -73,3 +73,15 @@ func (s clientRegister) failResult(msg string) Result {
 		s.debug,
 	)
 }
+
+func (s clientRegister) Name() string {
+	return s.stepName
+}
+
+func (s clientRegister) Requires() []string {
+	return []string{s.jwtClaimsCtxKey}
+}
+
+func (s clientRegister) Produces() []string {
+	return []string{s.responseCtxKey}
+}
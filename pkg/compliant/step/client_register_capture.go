@@ -0,0 +1,86 @@
+package step
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+// clientRegisterCapture puts the software client of a register response in context whatever the response status
+// or content type, so a client the ASPSP created is torn down even when the response assertions fail.
+// Responses without a client_id are ignored, the response body is left for the following steps.
+type clientRegisterCapture struct {
+	stepName          string
+	responseCtxKey    string
+	clientCtxKey      string
+	authoriserBuilder auth.AuthoriserBuilder
+}
+
+func NewClientRegisterCapture(responseCtxKey, clientCtxKey string, authoriserBuilder auth.AuthoriserBuilder) Step {
+	return clientRegisterCapture{
+		stepName:          "Capture registered software client",
+		responseCtxKey:    responseCtxKey,
+		clientCtxKey:      clientCtxKey,
+		authoriserBuilder: authoriserBuilder,
+	}
+}
+
+func (s clientRegisterCapture) Run(ctx Context) Result {
+	debug := NewDebug()
+	debug.Logf("get response object from ctx var: %s", s.responseCtxKey)
+	response, err := ctx.GetResponse(s.responseCtxKey)
+	if err != nil {
+		return NewFailResultWithDebug(
+			s.stepName,
+			fmt.Sprintf("getting response object from context: %s", err.Error()),
+			debug,
+		)
+	}
+
+	body, bodyCopy, err := http2.DrainBody(response.Body)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("reading register response: %s", err.Error()), debug)
+	}
+	response.Body = bodyCopy
+	raw, err := ioutil.ReadAll(body)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("reading register response: %s", err.Error()), debug)
+	}
+
+	var registration auth.OBClientRegistrationResponse
+	if err = json.Unmarshal(raw, &registration); err != nil || registration.ClientID == "" {
+		debug.Log("no client_id in register response, no software client was registered")
+		return NewPassResultWithDebug(s.stepName, debug)
+	}
+
+	// the response assertions and decoding that follow report what's wrong with the response
+	authoriser, err := s.authoriserBuilder.Build()
+	if err != nil {
+		debug.Logf("unable to capture software client %s: %v", registration.ClientID, err)
+		return NewPassResultWithDebug(s.stepName, debug)
+	}
+	client, err := authoriser.Client(raw)
+	if err != nil {
+		debug.Logf("unable to capture software client %s: %v", registration.ClientID, err)
+		return NewPassResultWithDebug(s.stepName, debug)
+	}
+
+	debug.Logf("setting software client %s in context var: %s", client.Id(), s.clientCtxKey)
+	ctx.SetClient(s.clientCtxKey, client)
+	return NewPassResultWithDebug(s.stepName, debug)
+}
+
+func (s clientRegisterCapture) Name() string {
+	return s.stepName
+}
+
+func (s clientRegisterCapture) Requires() []string {
+	return []string{s.responseCtxKey}
+}
+
+func (s clientRegisterCapture) Produces() []string {
+	return []string{s.clientCtxKey}
+}
@@ -0,0 +1,74 @@
+package step
+
+import (
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientRegisterCapture(t *testing.T) {
+	testCases := []struct {
+		name     string
+		status   int
+		body     string
+		clientId string
+	}{
+		{name: "created", status: http.StatusCreated, body: `{"client_id": "12345"}`, clientId: "12345"},
+		{name: "unexpected status", status: http.StatusOK, body: `{"client_id": "12345"}`, clientId: "12345"},
+		{name: "rejected", status: http.StatusBadRequest, body: `{"error": "invalid_client_metadata"}`},
+		{name: "not json", status: http.StatusCreated, body: `<html></html>`},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			ctx := NewContext()
+			response := &http.Response{StatusCode: tc.status, Body: ioutil.NopCloser(strings.NewReader(tc.body))}
+			ctx.SetResponse("response", response)
+			step := NewClientRegisterCapture("response", "clientCtxKey", captureAuthoriserBuilder(t))
+
+			result := step.Run(ctx)
+
+			assert.Equal(t, StatusPass, result.Status())
+			assert.Equal(t, "Capture registered software client", result.Name)
+			client, err := ctx.GetClient("clientCtxKey")
+			if tc.clientId == "" {
+				assert.Error(t, err)
+			} else {
+				require.NoError(t, err)
+				assert.Equal(t, tc.clientId, client.Id())
+			}
+			body, err := ioutil.ReadAll(response.Body)
+			require.NoError(t, err)
+			assert.Equal(t, tc.body, string(body))
+		})
+	}
+}
+
+func TestNewClientRegisterCapture_FailsIfResponseNotFoundInContext(t *testing.T) {
+	step := NewClientRegisterCapture("response", "clientCtxKey", captureAuthoriserBuilder(t))
+
+	result := step.Run(NewContext())
+
+	assert.Equal(t, StatusFail, result.Status())
+	assert.Equal(t, "getting response object from context: key not found in context", result.FailReason)
+}
+
+func captureAuthoriserBuilder(t *testing.T) auth.AuthoriserBuilder {
+	return auth.NewAuthoriserBuilder().
+		WithIssuer("softwareID").
+		WithKID("kid").
+		WithSSA("ssa").
+		WithPrivateKey(generateKey(t)).
+		WithTokenEndpointAuthMethod(jwt.SigningMethodPS256).
+		WithOpenIDConfig(openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"client_secret_basic"}}).
+		WithJwtExpiration(time.Hour)
+}
@@ -39,6 +39,7 @@ func (s clientRegisterResponse) Run(ctx Context) Result {
 
 	s.debug.Log("getting client")
 	s.debug.Logf("register res: %+v", string(body))
+	s.debug.LogJWTs(string(body))
 	authoriser, err := s.authoriserBuilder.Build()
 	if err != nil {
 		return NewFailResultWithDebug(
@@ -65,3 +66,15 @@ func (s clientRegisterResponse) failResult(msg string) Result {
 		s.debug,
 	)
 }
+
+func (s clientRegisterResponse) Name() string {
+	return s.stepName
+}
+
+func (s clientRegisterResponse) Requires() []string {
+	return []string{s.responseCtxKey}
+}
+
+func (s clientRegisterResponse) Produces() []string {
+	return []string{s.clientCtxKey}
+}
@@ -40,7 +40,7 @@ func (s clientRetrieve) Run(ctx Context) Result {
 	}
 
 	endpoint := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
-	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
 		msg := fmt.Sprintf("unable to make request: %s", err.Error())
 		return NewFailResultWithDebug(s.stepName, msg, debug)
@@ -57,7 +57,7 @@ func (s clientRetrieve) Run(ctx Context) Result {
 	}
 
 	debug.Log(http2.DebugRequest(req))
-	res, err := s.client.Do(req)
+	res, err := do(ctx, s.client, req, EndpointRetrieve)
 	if err != nil {
 		fapiInteractionId := ""
 		if res != nil {
@@ -70,3 +70,15 @@ func (s clientRetrieve) Run(ctx Context) Result {
 	ctx.SetResponse(s.responseCtxKey, res)
 	return NewPassResultWithDebug(s.stepName, debug)
 }
+
+func (s clientRetrieve) Name() string {
+	return s.stepName
+}
+
+func (s clientRetrieve) Requires() []string {
+	return []string{s.clientCtxKey}
+}
+
+func (s clientRetrieve) Produces() []string {
+	return []string{s.responseCtxKey}
+}
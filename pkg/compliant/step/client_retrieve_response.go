@@ -55,3 +55,15 @@ func (s clientRetrieveResponse) Run(ctx Context) Result {
 
 	return NewPassResult(s.stepName)
 }
+
+func (s clientRetrieveResponse) Name() string {
+	return s.stepName
+}
+
+func (s clientRetrieveResponse) Requires() []string {
+	return []string{s.responseCtxKey, s.clientCtxKey}
+}
+
+func (s clientRetrieveResponse) Produces() []string {
+	return []string{s.clientCtxKey}
+}
@@ -54,3 +54,15 @@ func (s clientRetrieveSchema) Run(ctx Context) Result {
 
 	return NewPassResultWithDebug(s.stepName, debug)
 }
+
+func (s clientRetrieveSchema) Name() string {
+	return s.stepName
+}
+
+func (s clientRetrieveSchema) Requires() []string {
+	return []string{s.responseCtxKey}
+}
+
+func (s clientRetrieveSchema) Produces() []string {
+	return nil
+}
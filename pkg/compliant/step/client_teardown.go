@@ -0,0 +1,81 @@
+package step
+
+import (
+	"fmt"
+	"net/http"
+
+	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+)
+
+// clientTeardown deletes the software client left in context by a scenario, unlike clientDelete
+// it doesn't fail when there is nothing to clean up
+type clientTeardown struct {
+	client               *http.Client
+	stepName             string
+	clientCtxKey         string
+	registrationEndpoint string
+}
+
+func NewClientTeardown(registrationEndpoint, clientCtxKey string, httpClient *http.Client) Step {
+	return clientTeardown{
+		stepName:             "Software client teardown",
+		client:               httpClient,
+		registrationEndpoint: registrationEndpoint,
+		clientCtxKey:         clientCtxKey,
+	}
+}
+
+func (s clientTeardown) Run(ctx Context) Result {
+	debug := NewDebug()
+
+	client, err := ctx.GetClient(s.clientCtxKey)
+	// a rejected registration leaves a client without id or registration access token in context
+	if err != nil || client.Id() == "" || client.RegistrationAccessToken() == "" {
+		return NewSkipResult(s.stepName, "no software client registered")
+	}
+	if deletedId, _ := ctx.GetString(deletedClientCtxKey(s.clientCtxKey)); deletedId == client.Id() {
+		return NewSkipResult(s.stepName, "software client already deleted by the scenario")
+	}
+
+	url := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, url, nil)
+	if err != nil {
+		return NewFailResult(s.stepName, fmt.Sprintf("unable to create request %s: %v", url, err))
+	}
+
+	err = dcr.AddRegistrationAccessTokenAuthHeader(req, client)
+	if err != nil {
+		return NewFailResult(s.stepName, fmt.Sprintf("unable to create request %s: %v", url, err))
+	}
+
+	debug.Log(http2.DebugRequest(req))
+
+	res, err := do(ctx, s.client, req, EndpointDelete)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, fmt.Sprintf("unable to call endpoint %s: %v", url, err), debug)
+	}
+	defer res.Body.Close()
+
+	debug.Log(http2.DebugResponse(res))
+
+	switch res.StatusCode {
+	case http.StatusNoContent:
+		return NewPassResultWithDebug(s.stepName, debug)
+	case http.StatusUnauthorized, http.StatusNotFound:
+		result := NewSkipResult(
+			s.stepName,
+			fmt.Sprintf("software client already deleted, status code %d", res.StatusCode),
+		)
+		result.Debug = *debug
+		return result
+	}
+
+	message := fmt.Sprintf("unable to delete software client %s, unexpected status code %d. x-fapi-interaction-id %s",
+		client.Id(), res.StatusCode, res.Header.Get("x-fapi-interaction-id"))
+	return NewFailResultWithDebug(s.stepName, message, debug)
+}
+
+func (s clientTeardown) Name() string {
+	return s.stepName
+}
@@ -0,0 +1,148 @@
+package step
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClientTeardown(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+		assert.Equal(t, fmt.Sprintf("/%s", clientID), r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, registrationAccessToken, clientSecret, server.URL))
+	step := NewClientTeardown(server.URL, "clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusPass, result.Status())
+	assert.Equal(t, "Software client teardown", result.Name)
+}
+
+func TestNewClientTeardown_SkipsWhenNoClient(t *testing.T) {
+	step := NewClientTeardown("localhost", "clientKey", &http.Client{})
+
+	result := step.Run(NewContext())
+
+	assert.Equal(t, StatusSkip, result.Status())
+	assert.Equal(t, "no software client registered", result.SkipReason)
+}
+
+func TestNewClientTeardown_SkipsWhenRegistrationWasRejected(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewTlsClientAuth("", "", "localhost"))
+	step := NewClientTeardown("localhost", "clientKey", &http.Client{})
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusSkip, result.Status())
+	assert.Equal(t, "no software client registered", result.SkipReason)
+}
+
+func TestNewClientTeardown_SkipsClientDeletedByScenario(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, registrationAccessToken, clientSecret, server.URL))
+	ctx.SetString(deletedClientCtxKey("clientKey"), clientID)
+	step := NewClientTeardown(server.URL, "clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusSkip, result.Status())
+	assert.Equal(t, "software client already deleted by the scenario", result.SkipReason)
+}
+
+func TestNewClientTeardown_DeletesClientRegisteredAgainAfterDelete(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, fmt.Sprintf("/%s", clientID), r.URL.EscapedPath())
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, registrationAccessToken, clientSecret, server.URL))
+	ctx.SetString(deletedClientCtxKey("clientKey"), "previous client")
+	step := NewClientTeardown(server.URL, "clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusPass, result.Status())
+}
+
+func TestNewClientTeardown_SkipsWhenAlreadyDeleted(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, registrationAccessToken, clientSecret, server.URL))
+	step := NewClientTeardown(server.URL, "clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusSkip, result.Status())
+	assert.Equal(t, "software client already deleted, status code 401", result.SkipReason)
+}
+
+func TestNewClientTeardown_FailsOnUnexpectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, registrationAccessToken, clientSecret, server.URL))
+	step := NewClientTeardown(server.URL, "clientKey", server.Client())
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusFail, result.Status())
+	assert.Contains(t, result.FailReason, "unable to delete software client foo, unexpected status code 500")
+}
+
+func TestNewClientTeardown_ClosesResponseBody(t *testing.T) {
+	body := &closeRecorder{Reader: strings.NewReader("")}
+	transport := roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return &http.Response{StatusCode: http.StatusNoContent, Header: http.Header{}, Body: body, Request: req}, nil
+	})
+
+	ctx := NewContext()
+	ctx.SetClient("clientKey", client.NewClientSecretBasic(clientID, registrationAccessToken, clientSecret, "localhost"))
+	step := NewClientTeardown("https://localhost", "clientKey", &http.Client{Transport: transport})
+
+	result := step.Run(ctx)
+
+	assert.Equal(t, StatusPass, result.Status())
+	assert.True(t, body.closed)
+}
+
+type roundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+type closeRecorder struct {
+	*strings.Reader
+	closed bool
+}
+
+func (c *closeRecorder) Close() error {
+	c.closed = true
+	return nil
+}
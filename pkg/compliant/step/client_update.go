@@ -54,7 +54,7 @@ func (s clientUpdate) Run(ctx Context) Result {
 	}
 
 	endpoint := fmt.Sprintf("%s/%s", s.registrationEndpoint, client.Id())
-	response, err := s.doJwtPutRequest(client, endpoint, jwtClaims)
+	response, err := s.doJwtPutRequest(ctx, client, endpoint, jwtClaims)
 	if err != nil {
 		return s.failResult(err.Error())
 	}
@@ -65,9 +65,13 @@ func (s clientUpdate) Run(ctx Context) Result {
 	return NewPassResultWithDebug(s.stepName, s.debug)
 }
 
-func (s clientUpdate) doJwtPutRequest(client dcr.Client, endpoint, jwtClaims string) (*http.Response, error) {
+func (s clientUpdate) doJwtPutRequest(
+	ctx Context,
+	client dcr.Client,
+	endpoint, jwtClaims string,
+) (*http.Response, error) {
 	body := bytes.NewBufferString(jwtClaims)
-	req, err := http.NewRequest(http.MethodPut, endpoint, body)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, body)
 	if err != nil {
 		return nil, errors.Wrap(err, "creating jose put request")
 	}
@@ -81,7 +85,7 @@ func (s clientUpdate) doJwtPutRequest(client dcr.Client, endpoint, jwtClaims str
 	s.debug.Log(http2.DebugRequest(req))
 
 	s.debug.Log("making request")
-	response, err := s.client.Do(req)
+	response, err := do(ctx, s.client, req, EndpointUpdate)
 	if err != nil {
 		return nil, errors.Wrap(err, "making jose put request")
 	}
@@ -97,3 +101,15 @@ func (s clientUpdate) failResult(msg string) Result {
 		s.debug,
 	)
 }
+
+func (s clientUpdate) Name() string {
+	return s.stepName
+}
+
+func (s clientUpdate) Requires() []string {
+	return []string{s.jwtClaimsCtxKey, s.clientCtxKey}
+}
+
+func (s clientUpdate) Produces() []string {
+	return []string{s.responseCtxKey}
+}
@@ -2,6 +2,7 @@ package step
 
 import (
 	"fmt"
+	"mime"
 )
 
 type assertContentType struct {
@@ -29,10 +30,24 @@ func (a assertContentType) Run(ctx Context) Result {
 		return NewFailResult(a.stepName, "Content-Type header is not present")
 	}
 
+	// parameters such as charset are ignored
 	contentType := response.Header.Get("Content-Type")
-	if contentType != a.contentType {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != a.contentType {
 		return NewFailResult(a.stepName, fmt.Sprintf("Content-Type is '%s'", contentType))
 	}
 
 	return NewPassResult(a.stepName)
 }
+
+func (a assertContentType) Name() string {
+	return a.stepName
+}
+
+func (a assertContentType) Requires() []string {
+	return []string{a.responseContextVar}
+}
+
+func (a assertContentType) Produces() []string {
+	return nil
+}
@@ -19,6 +19,17 @@ func TestAssertContentType_Pass(t *testing.T) {
 	assert.Equal(t, "Assert `Content-Type` header is application/vorgon", result.Name)
 }
 
+func TestAssertContentType_IgnoresParameters(t *testing.T) {
+	ctx := NewContext()
+	headers := http.Header{"Content-Type": []string{"application/json; charset=utf-8"}}
+	ctx.SetResponse("response", &http.Response{Header: headers})
+	step := NewAssertContentType("response", "application/json")
+
+	result := step.Run(ctx)
+
+	assert.True(t, result.Pass)
+}
+
 func TestAssertContentType_FailsIfResponseNotInContext(t *testing.T) {
 	ctx := NewContext()
 	step := NewAssertContentType("response", "application/vorgon")
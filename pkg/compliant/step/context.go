@@ -1,6 +1,7 @@
 package step
 
 import (
+	gocontext "context"
 	"errors"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
@@ -8,7 +9,12 @@ import (
 	"net/http"
 )
 
+// Context holds the values shared by the steps of a scenario, it is also the Go context
+// that cancels the steps outbound requests
 type Context interface {
+	gocontext.Context
+	// WithContext returns a context sharing the same values, cancelled by `parent` instead
+	WithContext(parent gocontext.Context) Context
 	SetString(key, value string)
 	GetString(key string) (string, error)
 	SetInt(key string, value int)
@@ -21,31 +27,49 @@ type Context interface {
 	GetClient(key string) (dcr.Client, error)
 	SetGrantToken(key string, token auth.GrantToken)
 	GetGrantToken(key string) (auth.GrantToken, error)
+	SetFailure(key, rootCause string)
+	GetFailure(key string) (string, error)
 }
 
 var ErrKeyNotFoundInContext = errors.New("key not found in context")
 
 type context struct {
+	gocontext.Context
 	strings       map[string]string
 	ints          map[string]int
 	responses     map[string]*http.Response
 	openIdConfigs map[string]openid.Configuration
 	clients       map[string]dcr.Client
 	grantTokens   map[string]auth.GrantToken
+	failures      map[string]string
 }
 
 func NewContext() Context {
+	return NewContextWithParent(gocontext.Background())
+}
+
+// NewContextWithParent creates an empty context that is cancelled with `parent`
+func NewContextWithParent(parent gocontext.Context) Context {
 	return &context{
+		Context:       parent,
 		strings:       map[string]string{},
 		ints:          map[string]int{},
 		responses:     map[string]*http.Response{},
 		openIdConfigs: map[string]openid.Configuration{},
 		clients:       map[string]dcr.Client{},
 		grantTokens:   map[string]auth.GrantToken{},
+		failures:      map[string]string{},
 	}
 }
 
+func (c *context) WithContext(parent gocontext.Context) Context {
+	withParent := *c
+	withParent.Context = parent
+	return &withParent
+}
+
 func (c *context) SetString(key, value string) {
+	delete(c.failures, key)
 	c.strings[key] = value
 }
 
@@ -58,6 +82,7 @@ func (c *context) GetString(key string) (string, error) {
 }
 
 func (c *context) SetInt(key string, value int) {
+	delete(c.failures, key)
 	c.ints[key] = value
 }
 
@@ -70,6 +95,7 @@ func (c *context) GetInt(key string) (int, error) {
 }
 
 func (c *context) SetResponse(key string, response *http.Response) {
+	delete(c.failures, key)
 	c.responses[key] = response
 }
 
@@ -82,6 +108,7 @@ func (c *context) GetResponse(key string) (*http.Response, error) {
 }
 
 func (c *context) SetOpenIdConfig(key string, config openid.Configuration) {
+	delete(c.failures, key)
 	c.openIdConfigs[key] = config
 }
 
@@ -94,6 +121,7 @@ func (c *context) GetOpenIdConfig(key string) (openid.Configuration, error) {
 }
 
 func (c *context) SetClient(key string, client dcr.Client) {
+	delete(c.failures, key)
 	c.clients[key] = client
 }
 
@@ -106,6 +134,7 @@ func (c *context) GetClient(key string) (dcr.Client, error) {
 }
 
 func (c *context) SetGrantToken(key string, token auth.GrantToken) {
+	delete(c.failures, key)
 	c.grantTokens[key] = token
 }
 
@@ -116,3 +145,17 @@ func (c *context) GetGrantToken(key string) (auth.GrantToken, error) {
 	}
 	return value, nil
 }
+
+// SetFailure records that the value for a key could not be produced,
+// `rootCause` identifies the step that failed. Setting a value for the key clears the failure.
+func (c *context) SetFailure(key, rootCause string) {
+	c.failures[key] = rootCause
+}
+
+func (c *context) GetFailure(key string) (string, error) {
+	value, ok := c.failures[key]
+	if !ok {
+		return "", ErrKeyNotFoundInContext
+	}
+	return value, nil
+}
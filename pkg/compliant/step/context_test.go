@@ -1,6 +1,7 @@
 package step
 
 import (
+	gocontext "context"
 	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
 	"net/http"
@@ -123,3 +124,46 @@ func TestContext_GetClient_ReturnsError_IfDoesntExists(t *testing.T) {
 
 	assert.Equal(t, ErrKeyNotFoundInContext, err)
 }
+
+func TestContext_SetFailure(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetFailure("key", "root cause")
+
+	rootCause, err := ctx.GetFailure("key")
+
+	assert.NoError(t, err)
+	assert.Equal(t, "root cause", rootCause)
+}
+
+func TestContext_GetFailure_ReturnsError_IfDoesntExists(t *testing.T) {
+	ctx := NewContext()
+
+	_, err := ctx.GetFailure("key")
+
+	assert.Equal(t, ErrKeyNotFoundInContext, err)
+}
+
+func TestContext_SetValue_ClearsFailure(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetFailure("key", "root cause")
+	ctx.SetString("key", "value")
+
+	_, err := ctx.GetFailure("key")
+
+	assert.Equal(t, ErrKeyNotFoundInContext, err)
+}
+
+func TestContext_WithContext_SharesValues(t *testing.T) {
+	parent, cancel := gocontext.WithCancel(gocontext.Background())
+	ctx := NewContextWithParent(parent)
+	ctx.SetString("key", "value")
+	cancel()
+
+	detached := ctx.WithContext(gocontext.Background())
+
+	assert.Equal(t, gocontext.Canceled, ctx.Err())
+	assert.NoError(t, detached.Err())
+	value, err := detached.GetString("key")
+	assert.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
@@ -41,10 +41,11 @@ func (a clientCredentialsGrant) Run(ctx Context) Result {
 		return NewFailResultWithDebug(a.stepName, msg, debug)
 	}
 
+	r = r.WithContext(ctx)
 	r.Header.Set("Content-type", "application/x-www-form-urlencoded")
 	debug.Log(http2.DebugRequest(r))
 
-	response, err := a.client.Do(r)
+	response, err := do(ctx, a.client, r, EndpointToken)
 	if err != nil {
 		message := fmt.Sprintf("error making token request call: %s", err.Error())
 		return NewFailResultWithDebug(a.stepName, message, debug)
@@ -68,3 +69,15 @@ func (a clientCredentialsGrant) Run(ctx Context) Result {
 
 	return NewPassResultWithDebug(a.stepName, debug)
 }
+
+func (a clientCredentialsGrant) Name() string {
+	return a.stepName
+}
+
+func (a clientCredentialsGrant) Requires() []string {
+	return []string{a.clientCtxKey}
+}
+
+func (a clientCredentialsGrant) Produces() []string {
+	return []string{a.grantTokenCtxKey}
+}
@@ -0,0 +1,53 @@
+package step
+
+// BlockedBy returns the root cause that prevents a step from running, it is found when any of the
+// values the step requires failed to be produced by a previous step.
+func BlockedBy(ctx Context, s Step) (string, bool) {
+	dependant, ok := s.(Dependant)
+	if !ok {
+		return "", false
+	}
+	for _, key := range dependant.Requires() {
+		rootCause, err := ctx.GetFailure(key)
+		if err == nil {
+			return rootCause, true
+		}
+	}
+	return "", false
+}
+
+// SetFailures records in the context that values produced by a step are not available due to `rootCause`
+func SetFailures(ctx Context, s Step, rootCause string) {
+	dependant, ok := s.(Dependant)
+	if !ok {
+		return
+	}
+	for _, key := range dependant.Produces() {
+		ctx.SetFailure(key, rootCause)
+	}
+}
+
+// Name returns the step name without running it
+func Name(s Step) string {
+	named, ok := s.(Named)
+	if !ok {
+		return "Unnamed step"
+	}
+	return named.Name()
+}
+
+func requires(s Step) []string {
+	dependant, ok := s.(Dependant)
+	if !ok {
+		return nil
+	}
+	return dependant.Requires()
+}
+
+func produces(s Step) []string {
+	dependant, ok := s.(Dependant)
+	if !ok {
+		return nil
+	}
+	return dependant.Produces()
+}
@@ -0,0 +1,41 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockedBy_ReturnsRootCauseOfMissingRequirement(t *testing.T) {
+	ctx := NewContext()
+	ctx.SetFailure("response", "register: Software client register")
+	step := NewAssertStatus(201, "response")
+
+	rootCause, blocked := BlockedBy(ctx, step)
+
+	assert.True(t, blocked)
+	assert.Equal(t, "register: Software client register", rootCause)
+}
+
+func TestBlockedBy_NotBlockedWithoutFailures(t *testing.T) {
+	step := NewAssertStatus(201, "response")
+
+	_, blocked := BlockedBy(NewContext(), step)
+
+	assert.False(t, blocked)
+}
+
+func TestSetFailures_MarksProducedKeys(t *testing.T) {
+	ctx := NewContext()
+	step := NewClientCredentialsGrant("grant", "client", "http://localhost/token", nil)
+
+	SetFailures(ctx, step, "root cause")
+
+	rootCause, err := ctx.GetFailure("grant")
+	assert.NoError(t, err)
+	assert.Equal(t, "root cause", rootCause)
+}
+
+func TestName(t *testing.T) {
+	assert.Equal(t, "Assert status code 201", Name(NewAssertStatus(201, "response")))
+}
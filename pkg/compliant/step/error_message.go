@@ -48,3 +48,15 @@ func (expectedErrorResponse AssertErrorMessage) Run(ctx Context) Result {
 	}
 	return NewPassResultWithDebug(expectedErrorResponse.StepName, debug)
 }
+
+func (expectedErrorResponse AssertErrorMessage) Name() string {
+	return expectedErrorResponse.StepName
+}
+
+func (expectedErrorResponse AssertErrorMessage) Requires() []string {
+	return []string{expectedErrorResponse.ResponseContextVar}
+}
+
+func (expectedErrorResponse AssertErrorMessage) Produces() []string {
+	return nil
+}
@@ -26,7 +26,11 @@ func (s getRequest) Run(ctx Context) Result {
 	debug := NewDebug()
 
 	debug.Logf("making get request to : %s", s.url)
-	r, err := s.httpClient.Get(s.url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return NewFailResultWithDebug(s.stepName, err.Error(), debug)
+	}
+	r, err := do(ctx, s.httpClient, req, fmt.Sprintf("GET %s", s.url))
 	if err != nil {
 		return NewFailResultWithDebug(s.stepName, err.Error(), debug)
 	}
@@ -37,3 +41,15 @@ func (s getRequest) Run(ctx Context) Result {
 
 	return NewPassResultWithDebug(s.stepName, debug)
 }
+
+func (s getRequest) Name() string {
+	return s.stepName
+}
+
+func (s getRequest) Requires() []string {
+	return nil
+}
+
+func (s getRequest) Produces() []string {
+	return []string{s.responseCtxKey}
+}
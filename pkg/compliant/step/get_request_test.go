@@ -1,6 +1,7 @@
 package step
 
 import (
+	gocontext "context"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
@@ -57,3 +58,18 @@ func TestGetRequest_SetsResponseInContext(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, []byte(`OK`), body)
 }
+
+func TestGetRequest_CancelledContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		t.Error("request should not be sent")
+	}))
+	defer server.Close()
+	parent, cancel := gocontext.WithCancel(gocontext.Background())
+	cancel()
+	step := NewGetRequest(server.URL, "response", server.Client())
+
+	result := step.Run(NewContextWithParent(parent))
+
+	assert.Equal(t, StatusFail, result.Status())
+	assert.Contains(t, result.FailReason, "context canceled")
+}
@@ -0,0 +1,140 @@
+package step
+
+import (
+	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+)
+
+// ClientLedger keeps track of software clients that still exist on the ASPSP
+type ClientLedger interface {
+	Add(client dcr.Client) error
+	Remove(client dcr.Client) error
+}
+
+// recordClient adds the software client produced by the wrapped step to a ledger
+type recordClient struct {
+	next         Step
+	clientCtxKey string
+	ledger       ClientLedger
+}
+
+func NewRecordClient(next Step, clientCtxKey string, ledger ClientLedger) Step {
+	return recordClient{
+		next:         next,
+		clientCtxKey: clientCtxKey,
+		ledger:       ledger,
+	}
+}
+
+func (s recordClient) Run(ctx Context) Result {
+	result := s.next.Run(ctx)
+	if result.Status() != StatusPass {
+		return result
+	}
+
+	client, err := ctx.GetClient(s.clientCtxKey)
+	if err != nil {
+		return result
+	}
+
+	// failing to record a client must not fail a registration that succeeded
+	if err := s.ledger.Add(client); err != nil {
+		result.Debug.Logf("unable to record software client %s in ledger: %v", client.Id(), err)
+		return result
+	}
+	result.Debug.Logf("recorded software client %s in ledger", client.Id())
+	return result
+}
+
+func (s recordClient) Name() string {
+	return Name(s.next)
+}
+
+func (s recordClient) Requires() []string {
+	return requires(s.next)
+}
+
+func (s recordClient) Produces() []string {
+	return produces(s.next)
+}
+
+// forgetClient removes the software client from a ledger once the wrapped step deleted it,
+// a skipped step is treated as the client being already gone
+type forgetClient struct {
+	next         Step
+	clientCtxKey string
+	ledger       ClientLedger
+}
+
+func NewForgetClient(next Step, clientCtxKey string, ledger ClientLedger) Step {
+	return forgetClient{
+		next:         next,
+		clientCtxKey: clientCtxKey,
+		ledger:       ledger,
+	}
+}
+
+func (s forgetClient) Run(ctx Context) Result {
+	result := s.next.Run(ctx)
+	if result.Status() != StatusPass && result.Status() != StatusSkip {
+		return result
+	}
+
+	client, err := ctx.GetClient(s.clientCtxKey)
+	if err != nil {
+		return result
+	}
+
+	if err := s.ledger.Remove(client); err != nil {
+		result.Debug.Logf("unable to remove software client %s from ledger: %v", client.Id(), err)
+		return result
+	}
+	result.Debug.Logf("removed software client %s from ledger", client.Id())
+	return result
+}
+
+func (s forgetClient) Name() string {
+	return Name(s.next)
+}
+
+func (s forgetClient) Requires() []string {
+	return requires(s.next)
+}
+
+func (s forgetClient) Produces() []string {
+	return produces(s.next)
+}
+
+// setClient puts a known software client in context, used to run steps against clients
+// not registered in the current run
+type setClient struct {
+	stepName     string
+	clientCtxKey string
+	client       dcr.Client
+}
+
+func NewSetClient(clientCtxKey string, client dcr.Client) Step {
+	return setClient{
+		stepName:     "Set software client",
+		clientCtxKey: clientCtxKey,
+		client:       client,
+	}
+}
+
+func (s setClient) Run(ctx Context) Result {
+	debug := NewDebug()
+	debug.Logf("setting software client %s in context var: %s", s.client.Id(), s.clientCtxKey)
+	ctx.SetClient(s.clientCtxKey, s.client)
+	return NewPassResultWithDebug(s.stepName, debug)
+}
+
+func (s setClient) Name() string {
+	return s.stepName
+}
+
+func (s setClient) Requires() []string {
+	return nil
+}
+
+func (s setClient) Produces() []string {
+	return []string{s.clientCtxKey}
+}
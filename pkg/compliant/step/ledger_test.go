@@ -0,0 +1,116 @@
+package step
+
+import (
+	"errors"
+	"testing"
+
+	dcr "github.com/OpenBankingUK/conformance-dcr/pkg/compliant/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type ledgerStub struct {
+	clients map[string]dcr.Client
+	err     error
+}
+
+func newLedgerStub() *ledgerStub {
+	return &ledgerStub{clients: map[string]dcr.Client{}}
+}
+
+func (l *ledgerStub) Add(client dcr.Client) error {
+	if l.err != nil {
+		return l.err
+	}
+	l.clients[client.Id()] = client
+	return nil
+}
+
+func (l *ledgerStub) Remove(client dcr.Client) error {
+	if l.err != nil {
+		return l.err
+	}
+	delete(l.clients, client.Id())
+	return nil
+}
+
+type resultStub struct {
+	result Result
+}
+
+func (s resultStub) Run(ctx Context) Result {
+	return s.result
+}
+
+func TestNewRecordClient_AddsClientOnPass(t *testing.T) {
+	ledger := newLedgerStub()
+	ctx := NewContext()
+	softClient := dcr.NewRegisteredClient(clientID, registrationAccessToken)
+	register := NewSetClient("clientKey", softClient)
+
+	result := NewRecordClient(register, "clientKey", ledger).Run(ctx)
+
+	assert.Equal(t, StatusPass, result.Status())
+	assert.Equal(t, "Set software client", result.Name)
+	assert.Contains(t, ledger.clients, clientID)
+}
+
+func TestNewRecordClient_IgnoresFailedStep(t *testing.T) {
+	ledger := newLedgerStub()
+	failed := resultStub{result: NewFailResult("register", "boom")}
+
+	result := NewRecordClient(failed, "clientKey", ledger).Run(NewContext())
+
+	assert.Equal(t, StatusFail, result.Status())
+	assert.Empty(t, ledger.clients)
+}
+
+func TestNewRecordClient_LedgerErrorDoesNotFailStep(t *testing.T) {
+	ledger := newLedgerStub()
+	ledger.err = errors.New("disk full")
+	register := NewSetClient("clientKey", dcr.NewRegisteredClient(clientID, registrationAccessToken))
+
+	result := NewRecordClient(register, "clientKey", ledger).Run(NewContext())
+
+	assert.Equal(t, StatusPass, result.Status())
+	require.NotEmpty(t, result.Debug.Item)
+	last := result.Debug.Item[len(result.Debug.Item)-1]
+	assert.Equal(t, "unable to record software client foo in ledger: disk full", last.Message)
+}
+
+func TestNewForgetClient_RemovesClientOnPassOrSkip(t *testing.T) {
+	for _, result := range []Result{NewPassResult("delete"), NewSkipResult("delete", "already deleted")} {
+		ledger := newLedgerStub()
+		softClient := dcr.NewRegisteredClient(clientID, registrationAccessToken)
+		require.NoError(t, ledger.Add(softClient))
+		ctx := NewContext()
+		ctx.SetClient("clientKey", softClient)
+
+		NewForgetClient(resultStub{result: result}, "clientKey", ledger).Run(ctx)
+
+		assert.Empty(t, ledger.clients)
+	}
+}
+
+func TestNewForgetClient_KeepsClientOnFail(t *testing.T) {
+	ledger := newLedgerStub()
+	softClient := dcr.NewRegisteredClient(clientID, registrationAccessToken)
+	require.NoError(t, ledger.Add(softClient))
+	ctx := NewContext()
+	ctx.SetClient("clientKey", softClient)
+
+	result := NewForgetClient(resultStub{result: NewFailResult("delete", "boom")}, "clientKey", ledger).Run(ctx)
+
+	assert.Equal(t, StatusFail, result.Status())
+	assert.Contains(t, ledger.clients, clientID)
+}
+
+func TestNewForgetClient_DelegatesDependencies(t *testing.T) {
+	teardown := NewClientDelete("localhost", "clientKey", "tokenKey", nil)
+
+	forget := NewForgetClient(teardown, "clientKey", newLedgerStub())
+
+	assert.Equal(t, Name(teardown), Name(forget))
+	assert.Equal(t, requires(teardown), requires(forget))
+	assert.Equal(t, produces(teardown), produces(forget))
+}
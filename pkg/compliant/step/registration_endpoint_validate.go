@@ -38,3 +38,7 @@ func (v registrationEndpointValidate) Run(ctx Context) Result {
 
 	return NewPassResult(v.stepName)
 }
+
+func (v registrationEndpointValidate) Name() string {
+	return v.stepName
+}
@@ -30,3 +30,15 @@ func (s setInvalidGrantToken) Run(ctx Context) Result {
 
 	return NewPassResultWithDebug(s.stepName, debug)
 }
+
+func (s setInvalidGrantToken) Name() string {
+	return s.stepName
+}
+
+func (s setInvalidGrantToken) Requires() []string {
+	return nil
+}
+
+func (s setInvalidGrantToken) Produces() []string {
+	return []string{s.grantTokenCtxKey}
+}
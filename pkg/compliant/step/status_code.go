@@ -40,3 +40,15 @@ func (a assertStatusCode) Run(ctx Context) Result {
 
 	return NewPassResultWithDebug(a.stepName, debug)
 }
+
+func (a assertStatusCode) Name() string {
+	return a.stepName
+}
+
+func (a assertStatusCode) Requires() []string {
+	return []string{a.responseContextVar}
+}
+
+func (a assertStatusCode) Produces() []string {
+	return nil
+}
@@ -3,24 +3,90 @@ package step
 import (
 	"fmt"
 	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
 )
 
 type Step interface {
 	Run(ctx Context) Result
 }
 
+// Named is implemented by steps that can report their name without being run
+type Named interface {
+	Name() string
+}
+
+// Dependant is implemented by steps that read or write values in the context,
+// it allows a runner to block a step when one of its inputs was never produced
+type Dependant interface {
+	Requires() []string
+	Produces() []string
+}
+
+type Status string
+
+const (
+	StatusPass    Status = "PASS"
+	StatusFail    Status = "FAIL"
+	StatusSkip    Status = "SKIP"
+	StatusBlocked Status = "BLOCKED"
+)
+
 type Result struct {
 	Name       string
 	Pass       bool
+	Skip       bool
+	Blocked    bool
 	FailReason string
+	SkipReason string
+	BlockedBy  string
 	Debug      DebugMessages
+	Timing
+	// Requests are the ASPSP endpoints called by the step
+	Requests []RequestTiming
+}
+
+func (r Result) Status() Status {
+	if r.Skip {
+		return StatusSkip
+	}
+	if r.Blocked {
+		return StatusBlocked
+	}
+	if r.Pass {
+		return StatusPass
+	}
+	return StatusFail
 }
 
 type Results []Result
 
 func (r Results) Fail() bool {
 	for _, result := range r {
-		if !result.Pass {
+		if result.Status() == StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Skipped is true when there is at least one result and all of them were skipped
+func (r Results) Skipped() bool {
+	if len(r) == 0 {
+		return false
+	}
+	for _, result := range r {
+		if result.Status() != StatusSkip {
+			return false
+		}
+	}
+	return true
+}
+
+// Blocked is true when at least one result was blocked
+func (r Results) Blocked() bool {
+	for _, result := range r {
+		if result.Status() == StatusBlocked {
 			return true
 		}
 	}
@@ -54,6 +120,14 @@ func (d *DebugMessages) Logf(format string, a ...interface{}) {
 	})
 }
 
+// LogJWTs logs the decoded header and claims of the JWTs found in message, if any
+func (d *DebugMessages) LogJWTs(message string) {
+	decoded := http.DebugJWTs(message)
+	if decoded != "" {
+		d.Log(decoded)
+	}
+}
+
 func NewPassResult(name string) Result {
 	return Result{Name: name, Pass: true}
 }
@@ -69,3 +143,13 @@ func NewFailResult(name, reason string) Result {
 func NewFailResultWithDebug(name, reason string, log *DebugMessages) Result {
 	return Result{Name: name, Pass: false, FailReason: reason, Debug: *log}
 }
+
+func NewSkipResult(name, reason string) Result {
+	return Result{Name: name, Skip: true, SkipReason: reason}
+}
+
+// NewBlockedResult is used for a step that was not run because a previous step failed,
+// `rootCause` points at the failed step
+func NewBlockedResult(name, rootCause string) Result {
+	return Result{Name: name, Blocked: true, BlockedBy: rootCause}
+}
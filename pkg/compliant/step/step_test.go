@@ -22,6 +22,40 @@ func TestNewFailResult(t *testing.T) {
 	assert.Equal(t, "computer says no", failingTest.FailReason)
 }
 
+func TestNewSkipResult(t *testing.T) {
+	skippedStep := NewSkipResult("some step", "not implemented")
+
+	assert.Equal(t, StatusSkip, skippedStep.Status())
+	assert.Equal(t, "some step", skippedStep.Name)
+	assert.Equal(t, "not implemented", skippedStep.SkipReason)
+	assert.Empty(t, skippedStep.FailReason)
+}
+
+func TestResult_Status(t *testing.T) {
+	assert.Equal(t, StatusPass, NewPassResult("pass").Status())
+	assert.Equal(t, StatusFail, NewFailResult("fail", "reason").Status())
+	assert.Equal(t, StatusSkip, NewSkipResult("skip", "reason").Status())
+}
+
+func TestResults_Fail_False_With_Skipped(t *testing.T) {
+	steps := Results{
+		NewPassResult("some step"),
+		NewSkipResult("skipped step", "not implemented"),
+	}
+
+	assert.False(t, steps.Fail())
+	assert.False(t, steps.Skipped())
+}
+
+func TestResults_Skipped_All_Skipped(t *testing.T) {
+	steps := Results{
+		NewSkipResult("skipped step", "not implemented"),
+	}
+
+	assert.True(t, steps.Skipped())
+	assert.False(t, Results{}.Skipped())
+}
+
 func TestResults_Fail_False_All_Passing(t *testing.T) {
 	passingSteps := Results{
 		NewPassResult("some step"),
@@ -0,0 +1,84 @@
+package step
+
+import (
+	gocontext "context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Timing is when a step, test case or scenario started and finished running
+type Timing struct {
+	Start time.Time
+	End   time.Time
+}
+
+// NewTiming is a timing that started at `start` and finished now
+func NewTiming(start time.Time) Timing {
+	return Timing{Start: start, End: time.Now()}
+}
+
+func (t Timing) Duration() time.Duration {
+	return t.End.Sub(t.Start)
+}
+
+// DCR endpoints requests are timed against, used to summarise latency per endpoint
+const (
+	EndpointRegister = "POST /register"
+	EndpointRetrieve = "GET /register/{ClientId}"
+	EndpointUpdate   = "PUT /register/{ClientId}"
+	EndpointDelete   = "DELETE /register/{ClientId}"
+	EndpointToken    = "POST /token"
+)
+
+// RequestTiming is how long an ASPSP endpoint took to respond to a request made by a step,
+// measured until the response headers are received
+type RequestTiming struct {
+	Endpoint   string
+	StatusCode int
+	Timing
+}
+
+type requestRecorderCtxKey struct{}
+
+type requestRecorder struct {
+	mutex    sync.Mutex
+	requests []RequestTiming
+}
+
+func (r *requestRecorder) record(request RequestTiming) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.requests = append(r.requests, request)
+}
+
+func (r *requestRecorder) recorded() []RequestTiming {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]RequestTiming{}, r.requests...)
+}
+
+// RecordRequests returns a context that records the requests made by steps run with it,
+// and a function returning the requests recorded so far
+func RecordRequests(ctx Context) (Context, func() []RequestTiming) {
+	recorder := &requestRecorder{}
+	return ctx.WithContext(gocontext.WithValue(ctx, requestRecorderCtxKey{}, recorder)), recorder.recorded
+}
+
+// do sends a request, timing it under `endpoint` when the context records requests
+func do(ctx Context, client *http.Client, req *http.Request, endpoint string) (*http.Response, error) {
+	start := time.Now()
+	res, err := client.Do(req)
+	timing := NewTiming(start)
+
+	recorder, ok := ctx.Value(requestRecorderCtxKey{}).(*requestRecorder)
+	if !ok {
+		return res, err
+	}
+	request := RequestTiming{Endpoint: endpoint, Timing: timing}
+	if err == nil {
+		request.StatusCode = res.StatusCode
+	}
+	recorder.record(request)
+	return res, err
+}
@@ -0,0 +1,47 @@
+package step
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTiming_Duration(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timing := Timing{Start: start, End: start.Add(150 * time.Millisecond)}
+
+	assert.Equal(t, 150*time.Millisecond, timing.Duration())
+}
+
+func TestRecordRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+	ctx, requests := RecordRequests(NewContext())
+	step := NewGetRequest(server.URL, "response", server.Client())
+
+	step.Run(ctx)
+
+	recorded := requests()
+	require.Len(t, recorded, 1)
+	assert.Equal(t, "GET "+server.URL, recorded[0].Endpoint)
+	assert.Equal(t, http.StatusCreated, recorded[0].StatusCode)
+	assert.False(t, recorded[0].Start.IsZero())
+	assert.True(t, recorded[0].Duration() >= 0)
+}
+
+func TestRecordRequests_SharesContextValues(t *testing.T) {
+	ctx := NewContext()
+	recordingCtx, _ := RecordRequests(ctx)
+
+	recordingCtx.SetString("key", "value")
+
+	value, err := ctx.GetString("key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", value)
+}
@@ -0,0 +1,34 @@
+package step
+
+import (
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+)
+
+type tokenEndpointAuthMethodValidate struct {
+	stepName          string
+	authoriserBuilder auth.AuthoriserBuilder
+}
+
+// NewValidateTokenEndpointAuthMethod checks the tool and the ASPSP agree on a token endpoint auth method,
+// failing with what the ASPSP advertised, what the tool supports and what was preferred otherwise
+func NewValidateTokenEndpointAuthMethod(authoriserBuilder auth.AuthoriserBuilder) Step {
+	return tokenEndpointAuthMethodValidate{
+		stepName:          "Token Endpoint Auth Method Validate",
+		authoriserBuilder: authoriserBuilder,
+	}
+}
+
+func (v tokenEndpointAuthMethodValidate) Run(ctx Context) Result {
+	method, err := v.authoriserBuilder.TokenEndpointAuthMethod()
+	if err != nil {
+		return NewFailResult(v.stepName, err.Error())
+	}
+
+	debug := NewDebug()
+	debug.Logf("token endpoint auth method %s", method)
+	return NewPassResultWithDebug(v.stepName, debug)
+}
+
+func (v tokenEndpointAuthMethodValidate) Name() string {
+	return v.stepName
+}
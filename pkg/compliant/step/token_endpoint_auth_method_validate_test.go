@@ -0,0 +1,35 @@
+package step
+
+import (
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewValidateTokenEndpointAuthMethod_ReturnsSuccessfulResult(t *testing.T) {
+	authoriserBuilder := auth.NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"private_key_jwt"}})
+
+	result := NewValidateTokenEndpointAuthMethod(authoriserBuilder).Run(NewContext())
+
+	assert.True(t, result.Pass)
+	assert.Equal(t, "Token Endpoint Auth Method Validate", result.Name)
+	require.Len(t, result.Debug.Item, 1)
+	assert.Equal(t, "token endpoint auth method private_key_jwt", result.Debug.Item[0].Message)
+}
+
+func TestNewValidateTokenEndpointAuthMethod_ReturnsFailureResultWithDiagnostic(t *testing.T) {
+	authoriserBuilder := auth.NewAuthoriserBuilder().
+		WithOpenIDConfig(openid.Configuration{TokenEndpointAuthMethodsSupported: []string{"none"}}).
+		WithPreferredTokenEndpointAuthMethod("tls_client_auth")
+
+	result := NewValidateTokenEndpointAuthMethod(authoriserBuilder).Run(NewContext())
+
+	assert.False(t, result.Pass)
+	assert.Equal(t, "Token Endpoint Auth Method Validate", result.Name)
+	assert.Contains(t, result.FailReason, "token_endpoint_auth_methods_supported advertises [none]")
+	assert.Contains(t, result.FailReason, "preferred_token_endpoint_auth_method is tls_client_auth")
+}
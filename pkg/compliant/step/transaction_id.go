@@ -32,3 +32,15 @@ func (a outputTransactionId) Run(ctx Context) Result {
 	debug.Logf("x-fapi-interaction-id: %s", transactionId)
 	return NewPassResultWithDebug(a.stepName, debug)
 }
+
+func (a outputTransactionId) Name() string {
+	return a.stepName
+}
+
+func (a outputTransactionId) Requires() []string {
+	return []string{a.responseContextVar}
+}
+
+func (a outputTransactionId) Produces() []string {
+	return nil
+}
@@ -1,7 +1,11 @@
 package compliant
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/http"
 )
 
 type TestCase interface {
@@ -9,15 +13,55 @@ type TestCase interface {
 }
 
 type TestCaseResult struct {
-	Name string
+	Name       string
+	SkipReason string
+	// AbortReason is set when the run was cancelled or timed out before all steps completed
+	AbortReason string
 	step.Results
+	step.Timing
+}
+
+func (r TestCaseResult) Status() step.Status {
+	if r.SkipReason != "" || r.Skipped() {
+		return step.StatusSkip
+	}
+	if r.Fail() {
+		return step.StatusFail
+	}
+	if r.Blocked() {
+		return step.StatusBlocked
+	}
+	return step.StatusPass
 }
 
 type TestCaseResults []TestCaseResult
 
 func (r TestCaseResults) Fail() bool {
 	for _, result := range r {
-		if result.Fail() {
+		if result.Status() == step.StatusFail {
+			return true
+		}
+	}
+	return false
+}
+
+// Skipped is true when there is at least one test case and all of them were skipped
+func (r TestCaseResults) Skipped() bool {
+	if len(r) == 0 {
+		return false
+	}
+	for _, result := range r {
+		if result.Status() != step.StatusSkip {
+			return false
+		}
+	}
+	return true
+}
+
+// Blocked is true when at least one test case was blocked
+func (r TestCaseResults) Blocked() bool {
+	for _, result := range r {
+		if result.Status() == step.StatusBlocked {
 			return true
 		}
 	}
@@ -25,8 +69,9 @@ func (r TestCaseResults) Fail() bool {
 }
 
 type testCase struct {
-	name  string
-	steps []step.Step
+	name       string
+	skipReason string
+	steps      []step.Step
 }
 
 func NewTestCase(name string, steps []step.Step) testCase {
@@ -36,14 +81,90 @@ func NewTestCase(name string, steps []step.Step) testCase {
 	}
 }
 
+// NewSkippedTestCase creates a test case that doesn't run any steps and reports as skipped with a reason
+func NewSkippedTestCase(name, reason string) testCase {
+	return testCase{
+		name:       name,
+		skipReason: reason,
+		steps:      []step.Step{},
+	}
+}
+
 func (t testCase) Run(ctx step.Context) TestCaseResult {
+	emit(ctx, Event{Type: EventTestCaseStarted, TestCase: t.name})
+
+	// requests are tagged with the scenario and test case that sent them when traffic is recorded
+	scope, _ := ctx.Value(eventScopeCtxKey).(eventScope)
+	tag := http.RecordingTag{ScenarioId: scope.scenarioId, TestCase: t.name, Teardown: scope.teardown}
+	ctx = ctx.WithContext(http.WithRecordingTag(ctx, tag))
+
+	start := time.Now()
+	result := t.run(ctx)
+	result.Timing = step.NewTiming(start)
+
+	emit(ctx, Event{Type: EventTestCaseFinished, TestCase: t.name, TestCaseResult: result})
+	return result
+}
+
+func (t testCase) run(ctx step.Context) TestCaseResult {
+	if t.skipReason != "" {
+		return TestCaseResult{
+			Name:       t.name,
+			SkipReason: t.skipReason,
+		}
+	}
+
 	var results step.Results
-	for _, step := range t.steps {
-		results = append(results, step.Run(ctx))
+	redactor := http.NewRedactor()
+	rootCause := ""
+	aborted := ""
+	for _, nextStep := range t.steps {
+		if rootCause == "" && ctx.Err() != nil {
+			rootCause = abortReason(ctx.Err())
+			aborted = rootCause
+		}
+		if rootCause == "" {
+			rootCause, _ = step.BlockedBy(ctx, nextStep)
+		}
+
+		// once a step fails or is blocked the remaining steps are not run
+		if rootCause != "" {
+			result := step.NewBlockedResult(step.Name(nextStep), rootCause)
+			result.Timing = step.NewTiming(time.Now())
+			results = append(results, result)
+			step.SetFailures(ctx, nextStep, rootCause)
+			emit(ctx, Event{Type: EventStepFinished, TestCase: t.name, StepResult: result})
+			continue
+		}
+
+		start := time.Now()
+		stepCtx, requests := step.RecordRequests(ctx)
+		result := nextStep.Run(stepCtx)
+		result.Timing = step.NewTiming(start)
+		result.Requests = requests()
+		if !redactionDisabled(ctx) {
+			result = redactResult(redactor, result)
+		}
+		if result.Status() == step.StatusFail && ctx.Err() != nil {
+			// the step was interrupted by the run being aborted, it didn't fail on its own
+			rootCause = abortReason(ctx.Err())
+			aborted = rootCause
+			blocked := step.NewBlockedResult(result.Name, rootCause)
+			blocked.Debug = result.Debug
+			result = blocked
+			step.SetFailures(ctx, nextStep, rootCause)
+		}
+		results = append(results, result)
+		if result.Status() == step.StatusFail {
+			rootCause = fmt.Sprintf("%s: %s", t.name, result.Name)
+			step.SetFailures(ctx, nextStep, rootCause)
+		}
+		emit(ctx, Event{Type: EventStepFinished, TestCase: t.name, StepResult: result})
 	}
 
 	return TestCaseResult{
-		Name:    t.name,
-		Results: results,
+		Name:        t.name,
+		AbortReason: aborted,
+		Results:     results,
 	}
 }
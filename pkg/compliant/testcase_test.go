@@ -1,6 +1,7 @@
 package compliant
 
 import (
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/auth"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/stretchr/testify/assert"
 	"testing"
@@ -46,8 +47,116 @@ func TestTestCase_Run_ReturnsOneResultPerTest(t *testing.T) {
 	assert.Len(t, result.Results, 2)
 }
 
+func TestTestCaseResults_Fail_FalseIfSkipped(t *testing.T) {
+	tcs := TestCaseResults{
+		TestCaseResult{
+			Name:       "skipped",
+			SkipReason: "not implemented",
+		},
+	}
+
+	assert.False(t, tcs.Fail())
+	assert.True(t, tcs.Skipped())
+	assert.Equal(t, step.StatusSkip, tcs[0].Status())
+}
+
+func TestTestCaseResult_Status(t *testing.T) {
+	pass := TestCaseResult{Results: step.Results{step.NewPassResult("pass")}}
+	fail := TestCaseResult{Results: step.Results{step.NewFailResult("fail", "reason")}}
+
+	assert.Equal(t, step.StatusPass, pass.Status())
+	assert.Equal(t, step.StatusFail, fail.Status())
+}
+
+func TestNewSkippedTestCase_DoesNotRunSteps(t *testing.T) {
+	tc := NewSkippedTestCase("test case", "not implemented")
+
+	result := tc.Run(step.NewContext())
+
+	assert.Equal(t, "test case", result.Name)
+	assert.Equal(t, "not implemented", result.SkipReason)
+	assert.Empty(t, result.Results)
+	assert.Equal(t, step.StatusSkip, result.Status())
+}
+
+func TestTestCase_Run_BlocksStepsAfterFailure(t *testing.T) {
+	ctx := step.NewContext()
+	steps := []step.Step{failStep{}, passStep{}}
+	tc := NewTestCase("test case", steps)
+
+	result := tc.Run(ctx)
+
+	assert.Len(t, result.Results, 2)
+	assert.Equal(t, step.StatusFail, result.Results[0].Status())
+	assert.Equal(t, step.StatusBlocked, result.Results[1].Status())
+	assert.Equal(t, "test case: test name", result.Results[1].BlockedBy)
+	assert.Equal(t, step.StatusFail, result.Status())
+}
+
+func TestTestCase_Run_BlockedByFailureInPreviousTestCase(t *testing.T) {
+	ctx := step.NewContext()
+	register := NewTestCase("register", []step.Step{
+		step.NewAssertStatus(201, responseCtxKey),
+		step.NewClientRegisterResponse(responseCtxKey, clientCtxKey, auth.NewAuthoriserBuilder()),
+	})
+	retrieve := NewTestCase("retrieve", []step.Step{
+		step.NewClientRetrieve(responseCtxKey, "http://localhost/register", clientCtxKey, "", nil),
+		step.NewAssertStatus(200, responseCtxKey),
+	})
+
+	registerResult := register.Run(ctx)
+	retrieveResult := retrieve.Run(ctx)
+
+	assert.Equal(t, step.StatusFail, registerResult.Status())
+	assert.Equal(t, step.StatusBlocked, retrieveResult.Status())
+	assert.False(t, TestCaseResults{retrieveResult}.Fail())
+	for _, result := range retrieveResult.Results {
+		assert.Equal(t, "register: Assert status code 201", result.BlockedBy)
+	}
+}
+
 type passStep struct{}
 
 func (s passStep) Run(ctx step.Context) step.Result {
 	return step.NewPassResult("test name")
 }
+
+func TestTestCase_Run_RecordsTimings(t *testing.T) {
+	tc := NewTestCase("test case", []step.Step{passStep{}, failStep{}, passStep{}})
+
+	result := tc.Run(step.NewContext())
+
+	assert.False(t, result.Start.IsZero())
+	assert.False(t, result.End.Before(result.Start))
+	for _, stepResult := range result.Results {
+		assert.False(t, stepResult.Start.IsZero())
+		assert.True(t, stepResult.Duration() >= 0)
+	}
+}
+
+type secretStep struct{}
+
+func (s secretStep) Run(ctx step.Context) step.Result {
+	debug := step.NewDebug()
+	debug.Log("Authorization: Bearer token")
+	return step.NewFailResultWithDebug("test name", `response {"client_secret":"secret"}`, debug)
+}
+
+func TestTestCase_Run_RedactsSecrets(t *testing.T) {
+	tc := NewTestCase("test case", []step.Step{secretStep{}})
+
+	result := tc.Run(step.NewContext())
+
+	assert.Equal(t, "Authorization: Bearer [REDACTED]", result.Results[0].Debug.Item[0].Message)
+	assert.Equal(t, `response {"client_secret":"[REDACTED]"}`, result.Results[0].FailReason)
+}
+
+func TestTestCase_Run_WithoutRedaction(t *testing.T) {
+	tc := NewTestCase("test case", []step.Step{secretStep{}})
+	ctx := step.NewContext()
+
+	result := tc.Run(ctx.WithContext(WithoutRedaction(ctx)))
+
+	assert.Equal(t, "Authorization: Bearer token", result.Results[0].Debug.Item[0].Message)
+	assert.Equal(t, `response {"client_secret":"secret"}`, result.Results[0].FailReason)
+}
@@ -1,21 +1,39 @@
 package compliant
 
+import (
+	"context"
+	"sync"
+)
+
 func NewTester() *tester {
 	return &tester{}
 }
 
+// ListenerFunc receives the result once the whole manifest has run
 type ListenerFunc func(result ManifestResult) error
 
 type tester struct {
-	listeners []ListenerFunc
+	listeners      []ListenerFunc
+	eventListeners []EventListenerFunc
 }
 
 func (t *tester) AddListener(listener ListenerFunc) {
 	t.listeners = append(t.listeners, listener)
 }
 
-func (t *tester) Compliant(manifest Manifest) (bool, error) {
-	result := manifest.Run()
+// AddEventListener streams events while the manifest runs, listeners are never called concurrently
+func (t *tester) AddEventListener(listener EventListenerFunc) {
+	t.eventListeners = append(t.eventListeners, listener)
+}
+
+// Compliant runs the manifest until it completes or `ctx` is done, an aborted run is never compliant
+func (t *tester) Compliant(ctx context.Context, manifest Manifest) (bool, error) {
+	dispatcher := newEventDispatcher(t.eventListeners)
+	if len(t.eventListeners) > 0 {
+		ctx = WithEventListener(ctx, dispatcher.dispatch)
+	}
+
+	result := manifest.Run(ctx)
 
 	for _, listener := range t.listeners {
 		err := listener(result)
@@ -24,5 +42,56 @@ func (t *tester) Compliant(manifest Manifest) (bool, error) {
 		}
 	}
 
-	return !result.Fail(), nil
+	if err := dispatcher.err(); err != nil {
+		return false, err
+	}
+
+	return !result.Fail() && !result.Aborted(), nil
+}
+
+// eventDispatcher serialises events from concurrent scenarios and keeps each listener's first error,
+// a failing listener stops receiving events while the others keep going
+type eventDispatcher struct {
+	mutex     sync.Mutex
+	listeners []EventListenerFunc
+	errs      []error
+}
+
+func newEventDispatcher(listeners []EventListenerFunc) *eventDispatcher {
+	return &eventDispatcher{
+		listeners: listeners,
+		errs:      make([]error, len(listeners)),
+	}
+}
+
+func (d *eventDispatcher) dispatch(event Event) error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var firstErr error
+	for i, listener := range d.listeners {
+		if d.errs[i] != nil {
+			continue
+		}
+		if err := listener(event); err != nil {
+			d.errs[i] = err
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+// err returns the error of the first listener, in registration order, that failed
+func (d *eventDispatcher) err() error {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	for _, err := range d.errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
@@ -1,9 +1,11 @@
 package compliant
 
 import (
+	"context"
 	"errors"
 	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"testing"
 )
 
@@ -21,7 +23,7 @@ func TestVerboseTester_Compliant(t *testing.T) {
 	assert.NoError(t, err)
 	tester := NewTester()
 
-	isCompliant, err := tester.Compliant(manifest)
+	isCompliant, err := tester.Compliant(context.Background(), manifest)
 
 	assert.NoError(t, err)
 	assert.False(t, isCompliant)
@@ -52,7 +54,7 @@ func TestVerboseTester_CallsListener(t *testing.T) {
 		return nil
 	})
 
-	isCompliant, err := tester.Compliant(manifest)
+	isCompliant, err := tester.Compliant(context.Background(), manifest)
 
 	assert.NoError(t, err)
 	assert.True(t, isCompliant)
@@ -76,8 +78,33 @@ func TestVerboseTester_HandlesListenerError(t *testing.T) {
 		return errors.New("boom")
 	})
 
-	isCompliant, err := tester.Compliant(manifest)
+	isCompliant, err := tester.Compliant(context.Background(), manifest)
 
 	assert.EqualError(t, err, "boom")
 	assert.False(t, isCompliant)
 }
+
+func TestVerboseTester_AbortedRunIsNotCompliant(t *testing.T) {
+	scenarios := Scenarios{
+		NewBuilder("#1", "Scenario with one test", "Spec Link").
+			TestCase(NewTestCaseBuilder("Test case").Step(passStep{}).Build()).
+			Build(),
+	}
+	manifest, err := NewManifest("test", "1.0", scenarios)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	tester := NewTester()
+	var result ManifestResult
+	tester.AddListener(func(r ManifestResult) error {
+		result = r
+		return nil
+	})
+
+	compliant, err := tester.Compliant(ctx, manifest)
+
+	require.NoError(t, err)
+	assert.False(t, compliant)
+	assert.True(t, result.Aborted())
+	assert.Equal(t, "run aborted: context canceled", result.AbortReason)
+}
@@ -1,22 +1,95 @@
 package compliant
 
 import (
-	"errors"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"fmt"
+	"strings"
+
 	"github.com/dgrijalva/jwt-go"
 )
 
-// resolves what token signing method to use based on .wellknown and supported
-func responseTokenSignMethod(methods *[]string) (jwt.SigningMethod, error) {
+// signing methods supported by the tool, in order of preference
+var supportedTokenSignMethods = []jwt.SigningMethod{
+	jwt.SigningMethodPS256,
+	jwt.SigningMethodPS384,
+	jwt.SigningMethodPS512,
+	jwt.SigningMethodES256,
+}
+
+// resolves what token signing method to use based on .wellknown, the preferred alg of the config and the
+// type of the signing key
+func responseTokenSignMethod(methods *[]string, preferredAlg string, key crypto.Signer) (jwt.SigningMethod, error) {
+	if preferredAlg != "" {
+		method := supportedTokenSignMethod(preferredAlg)
+		if method == nil {
+			return nil, fmt.Errorf("preferred signing alg %s is not supported", preferredAlg)
+		}
+		if methods != nil && !containsString(*methods, preferredAlg) {
+			return nil, fmt.Errorf("preferred signing alg %s is not advertised by the ASPSP", preferredAlg)
+		}
+		if !signMethodMatchesKey(method, key) {
+			return nil, fmt.Errorf("preferred signing alg %s does not match the signing key", preferredAlg)
+		}
+		return method, nil
+	}
+
 	if methods == nil {
-		return jwt.SigningMethodPS256, nil
+		return defaultTokenSignMethod(key)
 	}
 
 	for _, value := range *methods {
-		// we only support PS256 at the moment
-		if value == "PS256" {
-			return jwt.SigningMethodPS256, nil
+		method := supportedTokenSignMethod(value)
+		if method != nil && signMethodMatchesKey(method, key) {
+			return method, nil
 		}
 	}
 
-	return nil, errors.New("PS256 token sign method not found")
+	return nil, fmt.Errorf(
+		"no token sign method supported by the tool and the signing key found in %s",
+		strings.Join(*methods, ", "),
+	)
+}
+
+func supportedTokenSignMethod(alg string) jwt.SigningMethod {
+	for _, method := range supportedTokenSignMethods {
+		if method.Alg() == alg {
+			return method
+		}
+	}
+	return nil
+}
+
+func defaultTokenSignMethod(key crypto.Signer) (jwt.SigningMethod, error) {
+	for _, method := range supportedTokenSignMethods {
+		if signMethodMatchesKey(method, key) {
+			return method, nil
+		}
+	}
+	return nil, fmt.Errorf("signing key type %T is not supported", key)
+}
+
+// signMethodMatchesKey checks the key can sign with the method, RSA keys sign PS* and EC P-256 keys sign ES256,
+// a missing key is assumed to be RSA
+func signMethodMatchesKey(method jwt.SigningMethod, key crypto.Signer) bool {
+	switch k := key.(type) {
+	case nil, *rsa.PrivateKey:
+		_, isPSS := method.(*jwt.SigningMethodRSAPSS)
+		return isPSS
+	case *ecdsa.PrivateKey:
+		return k.Curve == elliptic.P256() && method == jwt.SigningMethodES256
+	default:
+		return false
+	}
+}
+
+func containsString(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
 }
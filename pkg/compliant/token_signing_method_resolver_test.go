@@ -1,25 +1,123 @@
 package compliant
 
 import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
 	"github.com/dgrijalva/jwt-go"
 	"github.com/stretchr/testify/assert"
-	"testing"
+	"github.com/stretchr/testify/require"
 )
 
 func TestResponseTokenSignMethod(t *testing.T) {
-	method, err := responseTokenSignMethod(nil)
-	assert.NoError(t, err)
-	assert.Equal(t, method, jwt.SigningMethodPS256)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	p384Key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	require.NoError(t, err)
 
-	method, err = responseTokenSignMethod(&[]string{"PS256"})
-	assert.NoError(t, err)
-	assert.Equal(t, method, jwt.SigningMethodPS256)
+	testCases := []struct {
+		name         string
+		methods      *[]string
+		preferredAlg string
+		key          crypto.Signer
+		expected     jwt.SigningMethod
+		expectedErr  string
+	}{
+		{
+			name:     "defaults to PS256 for rsa keys",
+			key:      rsaKey,
+			expected: jwt.SigningMethodPS256,
+		},
+		{
+			name:     "defaults to PS256 without key",
+			expected: jwt.SigningMethodPS256,
+		},
+		{
+			name:     "defaults to ES256 for ec keys",
+			key:      ecKey,
+			expected: jwt.SigningMethodES256,
+		},
+		{
+			name:     "first advertised method supported",
+			methods:  &[]string{"RS256", "PS512", "PS256"},
+			key:      rsaKey,
+			expected: jwt.SigningMethodPS512,
+		},
+		{
+			name:     "skips advertised methods not matching the key",
+			methods:  &[]string{"PS256", "ES256"},
+			key:      ecKey,
+			expected: jwt.SigningMethodES256,
+		},
+		{
+			name:        "no advertised method supported",
+			methods:     &[]string{"RS256"},
+			key:         rsaKey,
+			expectedErr: "no token sign method supported by the tool and the signing key found in RS256",
+		},
+		{
+			name:        "no advertised method matching the key",
+			methods:     &[]string{"PS256"},
+			key:         ecKey,
+			expectedErr: "no token sign method supported by the tool and the signing key found in PS256",
+		},
+		{
+			name:         "preferred alg",
+			methods:      &[]string{"PS256", "PS384"},
+			preferredAlg: "PS384",
+			key:          rsaKey,
+			expected:     jwt.SigningMethodPS384,
+		},
+		{
+			name:         "preferred alg without advertised methods",
+			preferredAlg: "ES256",
+			key:          ecKey,
+			expected:     jwt.SigningMethodES256,
+		},
+		{
+			name:         "preferred alg not supported",
+			preferredAlg: "RS256",
+			key:          rsaKey,
+			expectedErr:  "preferred signing alg RS256 is not supported",
+		},
+		{
+			name:         "preferred alg not advertised",
+			methods:      &[]string{"PS256"},
+			preferredAlg: "PS512",
+			key:          rsaKey,
+			expectedErr:  "preferred signing alg PS512 is not advertised by the ASPSP",
+		},
+		{
+			name:         "preferred alg not matching the key",
+			preferredAlg: "ES256",
+			key:          rsaKey,
+			expectedErr:  "preferred signing alg ES256 does not match the signing key",
+		},
+		{
+			name:        "ec key on an unsupported curve",
+			key:         p384Key,
+			expectedErr: "signing key type *ecdsa.PrivateKey is not supported",
+		},
+	}
 
-	method, err = responseTokenSignMethod(&[]string{"RS256", "PS256"})
-	assert.NoError(t, err)
-	assert.Equal(t, method, jwt.SigningMethodPS256)
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			method, err := responseTokenSignMethod(tc.methods, tc.preferredAlg, tc.key)
 
-	method, err = responseTokenSignMethod(&[]string{"RS256"})
-	assert.EqualError(t, err, "PS256 token sign method not found")
-	assert.Nil(t, method)
+			if tc.expectedErr != "" {
+				assert.EqualError(t, err, tc.expectedErr)
+				assert.Nil(t, method)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, method)
+		})
+	}
 }
@@ -77,6 +77,30 @@ func RootCASCertificate(pemBytes []byte) (*x509.Certificate, error) {
 	return cert, nil
 }
 
+// RootCASCertificates parses every certificate of a PEM bundle, such as a chain of intermediate and root CAs
+func RootCASCertificates(pemBytes []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	for {
+		var block *pem.Block
+		block, pemBytes = pem.Decode(pemBytes)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, errors.Wrapf(err, "parse x509 certificate %d", len(certs))
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, errors.New("could not find a PEM formatted block")
+	}
+	return certs, nil
+}
+
 func RootCASFromFile(path string) (*x509.Certificate, error) {
 	pemBytes, err := ioutil.ReadFile(path)
 	if err != nil {
@@ -86,14 +110,15 @@ func RootCASFromFile(path string) (*x509.Certificate, error) {
 	return RootCASCertificate(pemBytes)
 }
 
+// RootCAs parses the root CAs, each of them can be a bundle of several certificates
 func RootCAs(cas []string) ([]*x509.Certificate, error) {
-	rootCAs := make([]*x509.Certificate, len(cas))
+	var rootCAs []*x509.Certificate
 	for key, rootCA := range cas {
-		ca, err := RootCASCertificate([]byte(rootCA))
+		bundle, err := RootCASCertificates([]byte(rootCA))
 		if err != nil {
 			return nil, errors.Wrapf(err, "building rootCAs certificate: %d", key)
 		}
-		rootCAs[key] = ca
+		rootCAs = append(rootCAs, bundle...)
 	}
 	return rootCAs, nil
 }
@@ -5,6 +5,7 @@ import (
 	"crypto/x509"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
 	"net/http"
 	"testing"
 )
@@ -16,6 +17,31 @@ func TestRootCASCertificate(t *testing.T) {
 	assert.Nil(t, cert)
 }
 
+func TestRootCASCertificates_ParsesBundle(t *testing.T) {
+	root, err := ioutil.ReadFile("testdata/client-sample-root-ca.pem")
+	require.NoError(t, err)
+	cert, err := ioutil.ReadFile("testdata/client-sample-cert.pem")
+	require.NoError(t, err)
+
+	certs, err := RootCASCertificates([]byte(string(root) + "\n" + string(cert)))
+
+	require.NoError(t, err)
+	require.Len(t, certs, 2)
+	assert.NotEqual(t, certs[0].Raw, certs[1].Raw)
+}
+
+func TestRootCAs_FlattensBundles(t *testing.T) {
+	root, err := ioutil.ReadFile("testdata/client-sample-root-ca.pem")
+	require.NoError(t, err)
+	cert, err := ioutil.ReadFile("testdata/client-sample-cert.pem")
+	require.NoError(t, err)
+
+	rootCAs, err := RootCAs([]string{string(root) + "\n" + string(cert), string(root)})
+
+	require.NoError(t, err)
+	assert.Len(t, rootCAs, 3)
+}
+
 func TestTlsClientCertFromFile_HandlesKeyFileError(t *testing.T) {
 	certs, err := TlsCertFromFile(
 		"wrongfile",
@@ -11,7 +11,7 @@ func DebugRequest(req *http.Request) string {
 	if err != nil {
 		return fmt.Sprintf("cant debug request object: %s", err.Error())
 	}
-	return fmt.Sprintf("request:\n %s", string(debug))
+	return withDecodedJWTs(fmt.Sprintf("request:\n %s", string(debug)))
 }
 
 func DebugResponse(r *http.Response) string {
@@ -19,5 +19,13 @@ func DebugResponse(r *http.Response) string {
 	if err != nil {
 		return fmt.Sprintf("cant debug response object: %s", err.Error())
 	}
-	return fmt.Sprintf("response:\n %s", string(debug))
+	return withDecodedJWTs(fmt.Sprintf("response:\n %s", string(debug)))
+}
+
+func withDecodedJWTs(message string) string {
+	decoded := DebugJWTs(message)
+	if decoded == "" {
+		return message
+	}
+	return message + "\n" + decoded
 }
@@ -0,0 +1,298 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RecordingTag identifies the scenario and test case that sent a request, recorded with the request
+type RecordingTag struct {
+	ScenarioId string
+	TestCase   string
+	Teardown   bool
+}
+
+type recordingTagCtxKey struct{}
+
+// WithRecordingTag returns a context tagging the requests sent with it
+func WithRecordingTag(ctx context.Context, tag RecordingTag) context.Context {
+	return context.WithValue(ctx, recordingTagCtxKey{}, tag)
+}
+
+func NewHARRecorder(creatorName, creatorVersion string) *HARRecorder {
+	return &HARRecorder{
+		creator: harCreator{Name: creatorName, Version: creatorVersion},
+		entries: []harEntry{},
+		mutex:   &sync.Mutex{},
+	}
+}
+
+// HARRecorder records requests and responses sent through its round trippers as HAR 1.2 entries
+type HARRecorder struct {
+	creator  harCreator
+	entries  []harEntry
+	redactor *Redactor
+	mutex    *sync.Mutex
+}
+
+// WithRedactor masks credentials in recorded headers and bodies
+func (r *HARRecorder) WithRedactor(redactor Redactor) *HARRecorder {
+	r.redactor = &redactor
+	return r
+}
+
+// RoundTripper wraps `next` recording every request and response, `next` defaults to http.DefaultTransport
+func (r *HARRecorder) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return harRoundTripper{recorder: r, next: next}
+}
+
+// Write writes the recorded entries as a HAR file
+func (r *HARRecorder) Write(w io.Writer) error {
+	r.mutex.Lock()
+	har := harFile{Log: harLog{Version: "1.2", Creator: r.creator, Entries: r.entries}}
+	content, err := json.MarshalIndent(har, "", " ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+func (r *HARRecorder) record(entry harEntry) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.entries = append(r.entries, entry)
+}
+
+func (r *HARRecorder) redact(value string) string {
+	if r.redactor == nil {
+		return value
+	}
+	return r.redactor.Redact(value)
+}
+
+type harRoundTripper struct {
+	recorder *HARRecorder
+	next     http.RoundTripper
+}
+
+func (t harRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	requestBody, err := drainRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	res, err := t.next.RoundTrip(req)
+	wait := time.Since(start)
+
+	entry := harEntry{
+		StartedDateTime: start.Format(time.RFC3339Nano),
+		Time:            milliseconds(wait),
+		Request:         t.request(req, requestBody),
+		Response:        harResponse{Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1},
+		Cache:           struct{}{},
+		Timings:         harTimings{Send: 0, Wait: milliseconds(wait), Receive: 0},
+	}
+	if tag, ok := req.Context().Value(recordingTagCtxKey{}).(RecordingTag); ok {
+		entry.ScenarioId = tag.ScenarioId
+		entry.TestCase = tag.TestCase
+		entry.Teardown = tag.Teardown
+	}
+
+	if err != nil {
+		entry.Error = err.Error()
+		t.recorder.record(entry)
+		return res, err
+	}
+
+	responseBody, err := drainResponseBody(res)
+	if err != nil {
+		return nil, err
+	}
+	entry.Response = t.response(res, responseBody)
+	t.recorder.record(entry)
+	return res, nil
+}
+
+func (t harRoundTripper) request(req *http.Request, body []byte) harRequest {
+	request := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     t.headers(req.Header),
+		QueryString: []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+	query := req.URL.Query()
+	for _, name := range sortedKeys(query) {
+		for _, value := range query[name] {
+			request.QueryString = append(request.QueryString, harNameValue{Name: name, Value: value})
+		}
+	}
+	if body != nil {
+		request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     t.recorder.redact(string(body)),
+		}
+	}
+	return request
+}
+
+func (t harRoundTripper) response(res *http.Response, body []byte) harResponse {
+	return harResponse{
+		Status:      res.StatusCode,
+		StatusText:  strings.TrimSpace(strings.TrimPrefix(res.Status, fmt.Sprintf("%d", res.StatusCode))),
+		HTTPVersion: res.Proto,
+		Cookies:     []harNameValue{},
+		Headers:     t.headers(res.Header),
+		Content: harContent{
+			Size:     len(body),
+			MimeType: res.Header.Get("Content-Type"),
+			Text:     t.recorder.redact(string(body)),
+		},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+// headers are redacted as a "name: value" line, as they appear in a request dump
+func (t harRoundTripper) headers(header http.Header) []harNameValue {
+	headers := []harNameValue{}
+	for _, name := range sortedKeys(header) {
+		for _, value := range header[name] {
+			line := t.recorder.redact(fmt.Sprintf("%s: %s", name, value))
+			headers = append(headers, harNameValue{Name: name, Value: strings.TrimPrefix(line, name+": ")})
+		}
+	}
+	return headers
+}
+
+func sortedKeys(values map[string][]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func drainRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = req.Body.Close(); err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func drainResponseBody(res *http.Response) ([]byte, error) {
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if err = res.Body.Close(); err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+	ScenarioId      string      `json:"_scenarioId,omitempty"`
+	TestCase        string      `json:"_testCase,omitempty"`
+	Teardown        bool        `json:"_teardown,omitempty"`
+	Error           string      `json:"_error,omitempty"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
@@ -0,0 +1,108 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHARRecorder_RecordsRequestAndResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		assert.Equal(t, "grant_type=client_credentials", string(body))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"client_id":"123","registration_access_token":"rat"}`))
+	}))
+	defer server.Close()
+
+	recorder := NewHARRecorder("conformance-dcr", "v1.0.0").WithRedactor(NewRedactor())
+	client := &http.Client{Transport: recorder.RoundTripper(nil)}
+	ctx := WithRecordingTag(context.Background(), RecordingTag{ScenarioId: "DCR-001", TestCase: "register"})
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		server.URL+"/register?b=2&a=1",
+		strings.NewReader("grant_type=client_credentials"),
+	)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer token")
+
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	assert.Equal(t, `{"client_id":"123","registration_access_token":"rat"}`, string(body))
+
+	har := writeHAR(t, recorder)
+	assert.Equal(t, "1.2", har.Log.Version)
+	assert.Equal(t, harCreator{Name: "conformance-dcr", Version: "v1.0.0"}, har.Log.Creator)
+	require.Len(t, har.Log.Entries, 1)
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "DCR-001", entry.ScenarioId)
+	assert.Equal(t, "register", entry.TestCase)
+	assert.Equal(t, http.MethodPost, entry.Request.Method)
+	assert.Equal(t, server.URL+"/register?b=2&a=1", entry.Request.URL)
+	assert.Equal(t, []harNameValue{{"a", "1"}, {"b", "2"}}, entry.Request.QueryString)
+	assert.Equal(t, []harNameValue{
+		{"Authorization", "Bearer [REDACTED]"},
+		{"Content-Type", "application/x-www-form-urlencoded"},
+	}, entry.Request.Headers)
+	assert.Equal(t, "grant_type=client_credentials", entry.Request.PostData.Text)
+	assert.Equal(t, 201, entry.Response.Status)
+	assert.Equal(t, "Created", entry.Response.StatusText)
+	assert.Equal(t, `{"client_id":"123","registration_access_token":"[REDACTED]"}`, entry.Response.Content.Text)
+	assert.Equal(t, "application/json", entry.Response.Content.MimeType)
+}
+
+func TestHARRecorder_RedactsRegistrationRequest(t *testing.T) {
+	ssa := signedJWT(t, jwt.MapClaims{"software_id": "software"})
+	request := signedJWT(t, jwt.MapClaims{"aud": "aspsp", "software_statement": ssa})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	recorder := NewHARRecorder("conformance-dcr", "v1.0.0").WithRedactor(NewRedactor())
+	client := &http.Client{Transport: recorder.RoundTripper(nil)}
+	res, err := client.Post(server.URL+"/register", "application/jwt", strings.NewReader(request))
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+
+	har := writeHAR(t, recorder)
+	require.Len(t, har.Log.Entries, 1)
+	assert.Equal(t, "[REDACTED]", har.Log.Entries[0].Request.PostData.Text)
+}
+
+func TestHARRecorder_RecordsTransportErrors(t *testing.T) {
+	recorder := NewHARRecorder("conformance-dcr", "v1.0.0")
+	client := &http.Client{Transport: recorder.RoundTripper(nil)}
+
+	_, err := client.Get("http://127.0.0.1:1/register")
+	require.Error(t, err)
+
+	har := writeHAR(t, recorder)
+	require.Len(t, har.Log.Entries, 1)
+	assert.Equal(t, http.MethodGet, har.Log.Entries[0].Request.Method)
+	assert.Nil(t, har.Log.Entries[0].Request.PostData)
+	assert.Equal(t, 0, har.Log.Entries[0].Response.Status)
+	assert.NotEmpty(t, har.Log.Entries[0].Error)
+}
+
+func writeHAR(t *testing.T, recorder *HARRecorder) harFile {
+	out := &bytes.Buffer{}
+	require.NoError(t, recorder.Write(out))
+	var har harFile
+	require.NoError(t, json.Unmarshal(out.Bytes(), &har))
+	return har
+}
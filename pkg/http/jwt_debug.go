@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// nested jwt claims such as the software statement in a registration request are decoded too,
+// up to this depth
+const maxJWTDepth = 2
+
+// compactJWT matches a compact JWS or JWE along with the json or form field holding it, if any
+var compactJWT = regexp.MustCompile(`(?:"(\w+)"\s*:\s*"|\b(\w+)=)?(eyJ[A-Za-z0-9_-]+(?:\.[A-Za-z0-9_-]*){2,4})`)
+
+// DebugJWTs decodes the header and claims of every compact JWS found in message, and the header of every
+// compact JWE, without verifying them. Returns an empty string when message has no JWT.
+// Decoded JWTs name the field they were found in so the Redactor can mask the claims of secret ones.
+func DebugJWTs(message string) string {
+	return strings.Join(decodeJWTs(message, 0, map[string]bool{}), "\n")
+}
+
+func decodeJWTs(message string, depth int, seen map[string]bool) []string {
+	label := "jwt"
+	if depth > 0 {
+		label = "nested jwt"
+	}
+
+	var decoded []string
+	for _, match := range compactJWT.FindAllStringSubmatch(message, -1) {
+		token := match[3]
+		if seen[token] {
+			continue
+		}
+		seen[token] = true
+
+		parts := strings.Split(token, ".")
+		if len(parts) != 3 && len(parts) != 5 {
+			continue
+		}
+		header, err := decodeJSONSegment(parts[0])
+		if err != nil {
+			continue
+		}
+
+		from := ""
+		if field := match[1] + match[2]; field != "" {
+			from = " from " + field
+		}
+
+		if len(parts) == 5 {
+			decoded = append(decoded, fmt.Sprintf("decoded %s (jwe)%s:\nheader: %s\nclaims: encrypted", label, from, header))
+			continue
+		}
+		claims, err := decodeJSONSegment(parts[1])
+		if err != nil {
+			continue
+		}
+		decoded = append(decoded, fmt.Sprintf("decoded %s (jws)%s:\nheader: %s\nclaims: %s", label, from, header, claims))
+
+		if depth+1 < maxJWTDepth {
+			decoded = append(decoded, decodeJWTs(claims, depth+1, seen)...)
+		}
+	}
+	return decoded
+}
+
+func decodeJSONSegment(segment string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(segment, "="))
+	if err != nil {
+		return "", err
+	}
+	pretty := &bytes.Buffer{}
+	if err = json.Indent(pretty, raw, "", " "); err != nil {
+		return "", err
+	}
+	return pretty.String(), nil
+}
@@ -0,0 +1,73 @@
+package http
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func signedJWT(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = "kid"
+	signed, err := token.SignedString([]byte("key"))
+	require.NoError(t, err)
+	return signed
+}
+
+func TestDebugJWTs_DecodesNestedJWS(t *testing.T) {
+	ssa := signedJWT(t, jwt.MapClaims{"software_id": "software"})
+	request := signedJWT(t, jwt.MapClaims{"aud": "aspsp", "software_statement": ssa})
+
+	decoded := DebugJWTs("request:\n POST /register HTTP/1.1\r\n\r\n" + request)
+
+	expected := `decoded jwt (jws):
+header: {
+ "alg": "HS256",
+ "kid": "kid",
+ "typ": "JWT"
+}
+claims: {
+ "aud": "aspsp",
+ "software_statement": "` + ssa + `"
+}
+decoded nested jwt (jws) from software_statement:
+header: {
+ "alg": "HS256",
+ "kid": "kid",
+ "typ": "JWT"
+}
+claims: {
+ "software_id": "software"
+}`
+	assert.Equal(t, expected, decoded)
+}
+
+func TestDebugJWTs_DecodesJWEHeader(t *testing.T) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"RSA-OAEP","enc":"A256GCM"}`))
+
+	decoded := DebugJWTs(`{"id_token":"` + header + `.key.iv.ciphertext.tag"}`)
+
+	expected := "decoded jwt (jwe) from id_token:\n" +
+		"header: {\n \"alg\": \"RSA-OAEP\",\n \"enc\": \"A256GCM\"\n}\nclaims: encrypted"
+	assert.Equal(t, expected, decoded)
+}
+
+func TestDebugJWTs_IgnoresOtherValues(t *testing.T) {
+	assert.Equal(t, "", DebugJWTs("making request"))
+	assert.Equal(t, "", DebugJWTs("eyJub3QganNvbg.eyJ.sig"))
+}
+
+func TestDebugJWTs_RedactsSoftwareStatementClaims(t *testing.T) {
+	ssa := signedJWT(t, jwt.MapClaims{"software_id": "software"})
+	request := signedJWT(t, jwt.MapClaims{"aud": "aspsp", "software_statement": ssa})
+
+	redacted := NewRedactor().Redact(DebugJWTs("request:\n POST /register HTTP/1.1\r\n\r\n" + request))
+
+	assert.Contains(t, redacted, `"aud": "aspsp"`)
+	assert.Contains(t, redacted, "decoded nested jwt (jws) from software_statement:")
+	assert.NotContains(t, redacted, ssa)
+	assert.NotContains(t, redacted, "software_id")
+}
@@ -0,0 +1,86 @@
+package http
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+// secretFields are json and form fields whose values are credentials
+func secretFields() []string {
+	return []string{
+		"client_secret",
+		"registration_access_token",
+		"access_token",
+		"refresh_token",
+		"id_token",
+		"client_assertion",
+		"software_statement",
+	}
+}
+
+// Redactor masks credentials in debug messages, such as request and response dumps,
+// so they can be shared without leaking secrets
+type Redactor struct {
+	authorizationHeader *regexp.Regexp
+	jsonField           *regexp.Regexp
+	formField           *regexp.Regexp
+	privateKey          *regexp.Regexp
+	decodedJWTClaims    *regexp.Regexp
+	compactJWS          *regexp.Regexp
+}
+
+func NewRedactor() Redactor {
+	fields := strings.Join(secretFields(), "|")
+	return Redactor{
+		authorizationHeader: regexp.MustCompile(
+			`(?im)^((?:proxy-)?authorization:[ \t]*(?:(?:basic|bearer|digest)[ \t]+)?)[^\r\n]+`,
+		),
+		jsonField:  regexp.MustCompile(`("(?:` + fields + `)"\s*:\s*")(?:[^"\\]|\\.)*(")`),
+		formField:  regexp.MustCompile(`((?:^|[?&\s])(?:` + fields + `)=)[^&\s]*`),
+		privateKey: regexp.MustCompile(`(?s)(-----BEGIN [A-Z ]*PRIVATE KEY-----).*?(-----END [A-Z ]*PRIVATE KEY-----)`),
+		decodedJWTClaims: regexp.MustCompile(
+			`(?s)(decoded (?:nested )?jwt \(jws\) from (?:` + fields + `):\nheader: .*?\nclaims: )\{.*?\n\}`,
+		),
+		compactJWS: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.[A-Za-z0-9_-]*`),
+	}
+}
+
+// Redact masks authorization headers, credential json and form fields, claims decoded from credential fields,
+// JWS carrying credential claims, such as the registration request and its software statement, and private keys
+// in a message
+func (r Redactor) Redact(message string) string {
+	message = r.decodedJWTClaims.ReplaceAllString(message, "${1}"+redacted)
+	message = r.compactJWS.ReplaceAllStringFunc(message, func(token string) string {
+		if hasSecretClaim(token) {
+			return redacted
+		}
+		return token
+	})
+	message = r.authorizationHeader.ReplaceAllString(message, "${1}"+redacted)
+	message = r.jsonField.ReplaceAllString(message, "${1}"+redacted+"${2}")
+	message = r.formField.ReplaceAllString(message, "${1}"+redacted)
+	return r.privateKey.ReplaceAllString(message, "${1}"+redacted+"${2}")
+}
+
+// hasSecretClaim tells if the claims of a compact JWS hold one of the secret fields
+func hasSecretClaim(token string) bool {
+	parts := strings.Split(token, ".")
+	raw, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return false
+	}
+	var claims map[string]json.RawMessage
+	if err = json.Unmarshal(raw, &claims); err != nil {
+		return false
+	}
+	for _, field := range secretFields() {
+		if _, ok := claims[field]; ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,105 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// NewReplayTransport creates a transport answering requests with the responses of a HAR file recorded by HARRecorder
+func NewReplayTransport(har io.Reader) (*ReplayTransport, error) {
+	var file harFile
+	if err := json.NewDecoder(har).Decode(&file); err != nil {
+		return nil, errors.Wrap(err, "loading replay har file")
+	}
+
+	entries := make([]replayEntry, len(file.Log.Entries))
+	for i, entry := range file.Log.Entries {
+		entryURL, err := url.Parse(entry.Request.URL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "loading replay har file entry %d", i)
+		}
+		entries[i] = replayEntry{
+			key:   replayKey(entry.Request.Method, entryURL.Path, entry.ScenarioId),
+			entry: entry,
+		}
+	}
+
+	return &ReplayTransport{
+		entries: entries,
+		mutex:   &sync.Mutex{},
+	}, nil
+}
+
+// ReplayTransport answers requests without network access, matching them to recorded entries by method, path
+// and the scenario id of their recording tag. Each recorded entry is replayed once, in the recorded order,
+// so repeated requests get the responses they got when recorded.
+type ReplayTransport struct {
+	entries []replayEntry
+	mutex   *sync.Mutex
+}
+
+type replayEntry struct {
+	key   string
+	entry harEntry
+	used  bool
+}
+
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		if err := req.Body.Close(); err != nil {
+			return nil, err
+		}
+	}
+
+	tag, _ := req.Context().Value(recordingTagCtxKey{}).(RecordingTag)
+	entry, found := t.next(replayKey(req.Method, req.URL.Path, tag.ScenarioId))
+	if !found {
+		return nil, fmt.Errorf("no recorded response for %s %s in scenario %q", req.Method, req.URL.Path, tag.ScenarioId)
+	}
+	if entry.Error != "" {
+		return nil, fmt.Errorf("replaying recorded error: %s", entry.Error)
+	}
+
+	header := http.Header{}
+	for _, nameValue := range entry.Response.Headers {
+		header.Add(nameValue.Name, nameValue.Value)
+	}
+	return &http.Response{
+		Status:        strings.TrimSpace(fmt.Sprintf("%d %s", entry.Response.Status, entry.Response.StatusText)),
+		StatusCode:    entry.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(entry.Response.Content.Text)),
+		ContentLength: int64(len(entry.Response.Content.Text)),
+		Request:       req,
+	}, nil
+}
+
+func (t *ReplayTransport) next(key string) (harEntry, bool) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	for i := range t.entries {
+		if !t.entries[i].used && t.entries[i].key == key {
+			t.entries[i].used = true
+			return t.entries[i].entry, true
+		}
+	}
+	return harEntry{}, false
+}
+
+func replayKey(method, path, scenarioId string) string {
+	if scenarioId == "" {
+		return fmt.Sprintf("%s %s", method, path)
+	}
+	return fmt.Sprintf("%s %s (%s)", method, path, scenarioId)
+}
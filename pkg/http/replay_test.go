@@ -0,0 +1,81 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplayTransport_ReplaysRecordedResponses(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, _ = fmt.Fprintf(w, `{"call":%d}`, calls)
+	}))
+	recorder := NewHARRecorder("conformance-dcr", "test")
+	recordingClient := &http.Client{Transport: recorder.RoundTripper(nil)}
+	for _, scenarioId := range []string{"DCR-001", "DCR-001", "DCR-002"} {
+		doReplayRequest(t, recordingClient, server.URL, scenarioId)
+	}
+	server.Close()
+	har := &bytes.Buffer{}
+	require.NoError(t, recorder.Write(har))
+
+	transport, err := NewReplayTransport(har)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport}
+
+	res := doReplayRequest(t, client, "http://offline", "DCR-002")
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.Equal(t, "201 Created", res.Status)
+	assert.Equal(t, "application/json", res.Header.Get("Content-Type"))
+	assert.Equal(t, `{"call":3}`, readBody(t, res))
+	assert.Equal(t, `{"call":1}`, readBody(t, doReplayRequest(t, client, "http://offline", "DCR-001")))
+	assert.Equal(t, `{"call":2}`, readBody(t, doReplayRequest(t, client, "http://offline", "DCR-001")))
+
+	req, err := http.NewRequestWithContext(
+		WithRecordingTag(context.Background(), RecordingTag{ScenarioId: "DCR-001"}),
+		http.MethodPost,
+		"http://offline/register",
+		strings.NewReader("{}"),
+	)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.EqualError(
+		t,
+		err,
+		`Post "http://offline/register": no recorded response for POST /register in scenario "DCR-001"`,
+	)
+}
+
+func TestNewReplayTransport_HandlesInvalidHAR(t *testing.T) {
+	_, err := NewReplayTransport(strings.NewReader("not json"))
+
+	assert.EqualError(t, err, "loading replay har file: invalid character 'o' in literal null (expecting 'u')")
+}
+
+func doReplayRequest(t *testing.T, client *http.Client, baseURL, scenarioId string) *http.Response {
+	ctx := WithRecordingTag(context.Background(), RecordingTag{ScenarioId: scenarioId, TestCase: "register"})
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, baseURL+"/register", strings.NewReader("{}"))
+	require.NoError(t, err)
+	res, err := client.Do(req)
+	require.NoError(t, err)
+	return res
+}
+
+func readBody(t *testing.T, res *http.Response) string {
+	body, err := ioutil.ReadAll(res.Body)
+	require.NoError(t, err)
+	require.NoError(t, res.Body.Close())
+	return string(body)
+}
@@ -0,0 +1,274 @@
+package mockaspsp_test
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/openid"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	http2 "github.com/OpenBankingUK/conformance-dcr/pkg/http"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/mockaspsp"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockASPSP_PassesSpecManifests(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(mockaspsp.NewServer(env.ServerConfig()).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	testCases := []struct {
+		specVersion                      string
+		preferredTokenEndpointAuthMethod string
+	}{
+		{specVersion: "3.2"},
+		{specVersion: "3.3"},
+		{specVersion: "3.2", preferredTokenEndpointAuthMethod: "private_key_jwt"},
+		{specVersion: "3.3", preferredTokenEndpointAuthMethod: "private_key_jwt"},
+		{specVersion: "3.3", preferredTokenEndpointAuthMethod: "client_secret_basic"},
+		{specVersion: "3.3", preferredTokenEndpointAuthMethod: "client_secret_post"},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.specVersion+" "+tc.preferredTokenEndpointAuthMethod, func(t *testing.T) {
+			result := runManifest(t, env, server.URL, tc.specVersion, tc.preferredTokenEndpointAuthMethod)
+
+			assert.False(t, result.Fail())
+			assert.False(t, result.TeardownFail())
+			for _, scenario := range result.Results {
+				assert.Equal(t, step.StatusPass, scenario.Status(), "%s %s", scenario.Id, failReasons(scenario))
+			}
+		})
+	}
+}
+
+func TestMockASPSP_PassesSpecManifestsWithECKey(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	// the well-known advertises ES256, negotiated for the EC signing key
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(ecKey)
+	require.NoError(t, err)
+	env.Kid = "ec-signing-key"
+	env.SigningKeyPEM = string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	serverConfig := env.ServerConfig()
+	serverConfig.SigningKeys = map[string]crypto.PublicKey{env.Kid: &ecKey.PublicKey}
+
+	server := httptest.NewUnstartedServer(mockaspsp.NewServer(serverConfig).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	for _, preferredTokenEndpointAuthMethod := range []string{"tls_client_auth", "private_key_jwt"} {
+		preferredTokenEndpointAuthMethod := preferredTokenEndpointAuthMethod
+		t.Run(preferredTokenEndpointAuthMethod, func(t *testing.T) {
+			cfg := manifestConfig(t, env, server.URL, "3.3", preferredTokenEndpointAuthMethod)
+			assert.Equal(t, jwt.SigningMethodES256, cfg.TokenSigningMethod)
+
+			manifest, err := compliant.NewSpecManifest("3.3", cfg)
+			require.NoError(t, err)
+			result := manifest.Run(context.Background())
+
+			assert.False(t, result.Fail())
+			assert.False(t, result.TeardownFail())
+			for _, scenario := range result.Results {
+				assert.Equal(t, step.StatusPass, scenario.Status(), "%s %s", scenario.Id, failReasons(scenario))
+			}
+		})
+	}
+}
+
+func TestMockASPSP_PassesAlgorithmMatrix(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(mockaspsp.NewServer(env.ServerConfig()).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := manifestConfig(t, env, server.URL, "3.3", "")
+	manifest, err := compliant.NewAlgorithmMatrix(cfg)
+	require.NoError(t, err)
+
+	result := manifest.Run(context.Background())
+
+	assert.False(t, result.Fail())
+	assert.False(t, result.TeardownFail())
+	// 5 auth methods by 4 algs then 4 request object signing algs,
+	// ES256 is skipped on both axes as the environment signing key is RSA
+	require.Len(t, result.Results, 24)
+	statuses := map[step.Status]int{}
+	for _, scenario := range result.Results {
+		statuses[scenario.Status()]++
+		assert.NotEqual(t, step.StatusFail, scenario.Status(), "%s %s", scenario.Id, failReasons(scenario))
+	}
+	assert.Equal(t, map[step.Status]int{step.StatusPass: 18, step.StatusSkip: 6}, statuses)
+}
+
+func TestMockASPSP_PassesManifestFile(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(mockaspsp.NewServer(env.ServerConfig()).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	cfg := manifestConfig(t, env, server.URL, "3.3", "")
+	manifest, err := compliant.LoadManifestFile("../compliant/testdata/manifest.yaml", cfg)
+	require.NoError(t, err)
+
+	result := manifest.Run(context.Background())
+
+	assert.False(t, result.TeardownFail())
+	require.Len(t, result.Results, 3)
+	for _, scenario := range result.Results {
+		assert.Equal(t, step.StatusPass, scenario.Status(), "%s %s", scenario.Id, failReasons(scenario))
+	}
+}
+
+func TestMockASPSP_ReplaysRecordedRun(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(mockaspsp.NewServer(env.ServerConfig()).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	recorder := http2.NewHARRecorder("conformance-dcr", "test")
+	cfg := manifestConfig(t, env, server.URL, "3.3", "")
+	cfg.SecureClient.Transport = recorder.RoundTripper(cfg.SecureClient.Transport)
+	manifest, err := compliant.NewSpecManifest("3.3", cfg)
+	require.NoError(t, err)
+	recorded := manifest.Run(context.Background())
+
+	har := &bytes.Buffer{}
+	require.NoError(t, recorder.Write(har))
+	replay, err := http2.NewReplayTransport(har)
+	require.NoError(t, err)
+
+	replayCfg := manifestConfig(t, env, server.URL, "3.3", "")
+	replayCfg.SecureClient.Transport = replay
+	// the replayed run must not need the ASPSP
+	server.Close()
+	manifest, err = compliant.NewSpecManifest("3.3", replayCfg)
+	require.NoError(t, err)
+	replayed := manifest.Run(context.Background())
+
+	assert.False(t, recorded.Fail())
+	require.Len(t, replayed.Results, len(recorded.Results))
+	for i, scenario := range recorded.Results {
+		assert.Equal(t, scenario.Id, replayed.Results[i].Id)
+		assert.Equal(
+			t, scenario.Status(), replayed.Results[i].Status(), "%s %s", scenario.Id, failReasons(replayed.Results[i]),
+		)
+	}
+}
+
+func TestMockASPSP_RejectsRequestsWithoutClientCertificate(t *testing.T) {
+	env, err := mockaspsp.NewEnvironment()
+	require.NoError(t, err)
+
+	server := httptest.NewUnstartedServer(mockaspsp.NewServer(env.ServerConfig()).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	defer server.Close()
+
+	client := discoveryClient(env)
+	res, err := client.Get(server.URL + "/.well-known/openid-configuration")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+
+	res, err = client.Post(server.URL+"/register", "application/jwt", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func runManifest(
+	t *testing.T,
+	env mockaspsp.Environment,
+	serverURL, specVersion, preferredTokenEndpointAuthMethod string,
+) compliant.ManifestResult {
+	cfg := manifestConfig(t, env, serverURL, specVersion, preferredTokenEndpointAuthMethod)
+
+	manifest, err := compliant.NewSpecManifest(specVersion, cfg)
+	require.NoError(t, err)
+
+	return manifest.Run(context.Background())
+}
+
+func manifestConfig(
+	t *testing.T,
+	env mockaspsp.Environment,
+	serverURL, specVersion, preferredTokenEndpointAuthMethod string,
+) compliant.DCR32Config {
+	openIDConfig, err := openid.Get(serverURL+"/.well-known/openid-configuration", discoveryClient(env))
+	require.NoError(t, err)
+
+	cfg, err := compliant.NewDCR32Config(
+		openIDConfig,
+		env.SSA,
+		env.Audience,
+		env.Kid,
+		env.SoftwareId,
+		env.RedirectURIs,
+		env.SigningKeyPEM,
+		env.TransportKeyPEM,
+		env.TransportCertPEM,
+		"",
+		[]string{env.CACertPEM},
+		true,
+		true,
+		true,
+		false,
+		specVersion,
+		preferredTokenEndpointAuthMethod,
+		"",
+		"",
+		false,
+		"",
+	)
+	require.NoError(t, err)
+	return cfg
+}
+
+func discoveryClient(env mockaspsp.Environment) *http.Client {
+	rootCAs := x509.NewCertPool()
+	rootCAs.AppendCertsFromPEM([]byte(env.CACertPEM))
+	return &http.Client{
+		Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: rootCAs, MinVersion: tls.VersionTLS12}},
+	}
+}
+
+func failReasons(scenario compliant.ScenarioResult) []string {
+	var reasons []string
+	for _, testCase := range scenario.TestCaseResults {
+		for _, result := range testCase.Results {
+			if result.FailReason != "" {
+				reasons = append(reasons, testCase.Name+": "+result.FailReason)
+			}
+		}
+	}
+	return reasons
+}
@@ -0,0 +1,79 @@
+package mockaspsp
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/pki"
+	"github.com/pkg/errors"
+)
+
+// Environment is a self-contained set of keys, certificates and software statement shared by a TPP running the tool
+// and the mock ASPSP, so the tool can run against the mock without OB Directory credentials
+type Environment struct {
+	// Audience is the ASPSP identifier to put in the registration requests
+	Audience string
+	// SoftwareId identifies the software statement, it's the issuer of the registration requests
+	SoftwareId   string
+	SSA          string
+	Kid          string
+	RedirectURIs []string
+	// SigningKeyPEM is the private key signing the registration requests
+	SigningKeyPEM    string
+	TransportCertPEM string
+	TransportKeyPEM  string
+	// CACertPEM is the root CA of the server and transport certificates
+	CACertPEM  string
+	ServerCert tls.Certificate
+
+	caCert       *x509.Certificate
+	signingKey   *rsa.PrivateKey
+	directoryKey *rsa.PrivateKey
+}
+
+// NewEnvironment generates a PKI with a server certificate for localhost
+func NewEnvironment() (Environment, error) {
+	p, err := pki.Generate(pki.DefaultOptions())
+	if err != nil {
+		return Environment{}, errors.Wrap(err, "creating mock environment")
+	}
+	return EnvironmentFromPKI(p), nil
+}
+
+// EnvironmentFromPKI shares the software statement, transport and signing keys of a PKI with the mock ASPSP,
+// which serves the PKI server certificate and trusts its directory key and root CA
+func EnvironmentFromPKI(p pki.PKI) Environment {
+	return Environment{
+		Audience:         p.Audience,
+		SoftwareId:       p.SoftwareId,
+		SSA:              p.SSA,
+		Kid:              p.Signing.Kid(),
+		RedirectURIs:     p.RedirectURIs,
+		SigningKeyPEM:    p.Signing.KeyPEM(),
+		TransportCertPEM: p.Transport.CertPEM(),
+		TransportKeyPEM:  p.Transport.KeyPEM(),
+		CACertPEM:        p.RootCA.CertPEM(),
+		ServerCert:       p.Server.TLSCertificate(),
+		caCert:           p.RootCA.Cert,
+		signingKey:       p.Signing.Key,
+		directoryKey:     p.Directory.Key,
+	}
+}
+
+// ServerConfig trusts the directory key of the environment and the TPP signing key
+func (e Environment) ServerConfig() Config {
+	return Config{
+		Audience:             e.Audience,
+		SoftwareStatementKey: &e.directoryKey.PublicKey,
+		SigningKeys:          map[string]crypto.PublicKey{e.Kid: &e.signingKey.PublicKey},
+	}
+}
+
+// TLSConfig serves the server certificate and verifies client certificates issued by the environment CA
+func (e Environment) TLSConfig() *tls.Config {
+	clientCAs := x509.NewCertPool()
+	clientCAs.AddCert(e.caCert)
+	return TLSConfig(e.ServerCert, clientCAs)
+}
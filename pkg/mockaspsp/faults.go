@@ -0,0 +1,112 @@
+package mockaspsp
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Fault makes the mock ASPSP misbehave in one way, to check the scenarios detect the defect they target
+type Fault string
+
+const (
+	// FaultAcceptExpiredJWT registers clients with expired registration requests
+	FaultAcceptExpiredJWT Fault = "accept-expired-jwt"
+	// FaultIgnoreSignature doesn't verify the signatures of registration requests and software statements
+	FaultIgnoreSignature Fault = "ignore-signature"
+	// FaultCreatedAsOK answers a successful registration with 200 instead of 201
+	FaultCreatedAsOK Fault = "ok-instead-of-created"
+	// FaultOmitClientId leaves client_id out of the registration responses
+	FaultOmitClientId Fault = "omit-client-id"
+	// FaultWrongContentType sends the json responses as text/plain
+	FaultWrongContentType Fault = "wrong-content-type"
+	// FaultLeakSecrets echoes the registration request, and the software statement it carries, in error responses
+	FaultLeakSecrets Fault = "leak-secrets-in-errors"
+	// FaultSlowResponses delays every response by the SlowResponseDelay of the config
+	FaultSlowResponses Fault = "slow-responses"
+)
+
+// DefaultSlowResponseDelay is longer than the 10 seconds the tool waits for a response
+const DefaultSlowResponseDelay = 15 * time.Second
+
+// Faults lists every fault the mock ASPSP can inject
+func Faults() []Fault {
+	return []Fault{
+		FaultAcceptExpiredJWT,
+		FaultIgnoreSignature,
+		FaultCreatedAsOK,
+		FaultOmitClientId,
+		FaultWrongContentType,
+		FaultLeakSecrets,
+		FaultSlowResponses,
+	}
+}
+
+// ParseFaults parses a comma separated list of faults
+func ParseFaults(value string) ([]Fault, error) {
+	var faults []Fault
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		fault, ok := knownFault(name)
+		if !ok {
+			return nil, fmt.Errorf("unknown mock fault %q", name)
+		}
+		faults = append(faults, fault)
+	}
+	return faults, nil
+}
+
+func knownFault(name string) (Fault, bool) {
+	for _, fault := range Faults() {
+		if string(fault) == name {
+			return fault, true
+		}
+	}
+	return "", false
+}
+
+func (s *Server) fault(fault Fault) bool {
+	for _, item := range s.cfg.Faults {
+		if item == fault {
+			return true
+		}
+	}
+	return false
+}
+
+// injectFaults applies the faults affecting every response
+func (s *Server) injectFaults(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.fault(FaultSlowResponses) {
+			delay := s.cfg.SlowResponseDelay
+			if delay == 0 {
+				delay = DefaultSlowResponseDelay
+			}
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if s.fault(FaultWrongContentType) {
+			w = textPlainWriter{w}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// textPlainWriter replaces the Content-Type of responses with a body
+type textPlainWriter struct {
+	http.ResponseWriter
+}
+
+func (w textPlainWriter) WriteHeader(statusCode int) {
+	if w.Header().Get("Content-Type") != "" {
+		w.Header().Set("Content-Type", "text/plain")
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
@@ -0,0 +1,146 @@
+package mockaspsp_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/compliant/step"
+	"github.com/OpenBankingUK/conformance-dcr/pkg/mockaspsp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMockASPSP_ScenariosDetectFaults is mutation testing of the scenarios, each fault of the mock must fail the
+// scenarios targeting it, and must not affect the others
+func TestMockASPSP_ScenariosDetectFaults(t *testing.T) {
+	testCases := []struct {
+		fault         mockaspsp.Fault
+		failScenarios []string
+		passScenarios []string
+	}{
+		{
+			fault:         mockaspsp.FaultAcceptExpiredJWT,
+			failScenarios: []string{"DCR-004"},
+			passScenarios: []string{"DCR-001", "DCR-002", "DCR-012", "DCR-013"},
+		},
+		{
+			fault:         mockaspsp.FaultIgnoreSignature,
+			failScenarios: []string{"DCR-012", "DCR-013"},
+			passScenarios: []string{"DCR-001", "DCR-002", "DCR-004"},
+		},
+		{
+			fault:         mockaspsp.FaultCreatedAsOK,
+			failScenarios: []string{"DCR-002", "DCR-003", "DCR-005", "DCR-008"},
+			passScenarios: []string{"DCR-001", "DCR-004", "DCR-012", "DCR-013"},
+		},
+		{
+			fault:         mockaspsp.FaultOmitClientId,
+			failScenarios: []string{"DCR-002", "DCR-003", "DCR-005", "DCR-008"},
+			passScenarios: []string{"DCR-001", "DCR-004", "DCR-012", "DCR-013"},
+		},
+		{
+			fault:         mockaspsp.FaultWrongContentType,
+			failScenarios: []string{"DCR-002", "DCR-003", "DCR-005", "DCR-008"},
+			passScenarios: []string{"DCR-001"},
+		},
+		{
+			fault:         mockaspsp.FaultLeakSecrets,
+			failScenarios: []string{"DCR-004", "DCR-012", "DCR-013"},
+			passScenarios: []string{"DCR-001", "DCR-002", "DCR-005"},
+		},
+		{
+			fault:         mockaspsp.FaultSlowResponses,
+			failScenarios: []string{"DCR-002", "DCR-004", "DCR-012", "DCR-013"},
+			passScenarios: []string{"DCR-001"},
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(string(tc.fault), func(t *testing.T) {
+			env, err := mockaspsp.NewEnvironment()
+			require.NoError(t, err)
+			serverConfig := env.ServerConfig()
+			serverConfig.Faults = []mockaspsp.Fault{tc.fault}
+			serverConfig.SlowResponseDelay = 300 * time.Millisecond
+
+			server := httptest.NewUnstartedServer(mockaspsp.NewServer(serverConfig).Handler())
+			server.TLS = env.TLSConfig()
+			server.StartTLS()
+			defer server.Close()
+
+			cfg := manifestConfig(t, env, server.URL, "3.2", "")
+			// shorter than the slow responses delay, the tool waits 10 seconds otherwise
+			cfg.SecureClient.Timeout = 100 * time.Millisecond
+			manifest, err := compliant.NewSpecManifest("3.2", cfg)
+			require.NoError(t, err)
+
+			statuses := map[string]step.Status{}
+			for _, scenario := range manifest.Run(context.Background()).Results {
+				statuses[scenario.Id] = scenario.Status()
+			}
+			for _, id := range tc.failScenarios {
+				assert.Equal(t, step.StatusFail, statuses[id], "%s should detect %s", id, tc.fault)
+			}
+			for _, id := range tc.passScenarios {
+				assert.Equal(t, step.StatusPass, statuses[id], "%s should not be affected by %s", id, tc.fault)
+			}
+		})
+	}
+}
+
+// TestMockASPSP_TeardownDeletesClientsOfFailedScenarios checks a client the ASPSP created is deleted even when
+// the registration response fails the scenario
+func TestMockASPSP_TeardownDeletesClientsOfFailedScenarios(t *testing.T) {
+	testCases := []struct {
+		name   string
+		faults []mockaspsp.Fault
+	}{
+		{name: "no fault"},
+		{name: string(mockaspsp.FaultCreatedAsOK), faults: []mockaspsp.Fault{mockaspsp.FaultCreatedAsOK}},
+		{name: string(mockaspsp.FaultWrongContentType), faults: []mockaspsp.Fault{mockaspsp.FaultWrongContentType}},
+		{name: string(mockaspsp.FaultAcceptExpiredJWT), faults: []mockaspsp.Fault{mockaspsp.FaultAcceptExpiredJWT}},
+		{name: string(mockaspsp.FaultIgnoreSignature), faults: []mockaspsp.Fault{mockaspsp.FaultIgnoreSignature}},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			env, err := mockaspsp.NewEnvironment()
+			require.NoError(t, err)
+			serverConfig := env.ServerConfig()
+			serverConfig.Faults = tc.faults
+			mock := mockaspsp.NewServer(serverConfig)
+
+			server := httptest.NewUnstartedServer(mock.Handler())
+			server.TLS = env.TLSConfig()
+			server.StartTLS()
+			defer server.Close()
+
+			cfg := manifestConfig(t, env, server.URL, "3.2", "")
+			manifest, err := compliant.NewSpecManifest("3.2", cfg)
+			require.NoError(t, err)
+
+			result := manifest.Run(context.Background())
+
+			assert.False(t, result.TeardownFail())
+			assert.Equal(t, 0, mock.RegisteredClients())
+		})
+	}
+}
+
+func TestParseFaults(t *testing.T) {
+	faults, err := mockaspsp.ParseFaults(" ignore-signature,slow-responses ,")
+	require.NoError(t, err)
+	assert.Equal(t, []mockaspsp.Fault{mockaspsp.FaultIgnoreSignature, mockaspsp.FaultSlowResponses}, faults)
+
+	faults, err = mockaspsp.ParseFaults("")
+	require.NoError(t, err)
+	assert.Empty(t, faults)
+
+	_, err = mockaspsp.ParseFaults("ignore-signature,teapot")
+	assert.EqualError(t, err, `unknown mock fault "teapot"`)
+}
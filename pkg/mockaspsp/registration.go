@@ -0,0 +1,330 @@
+package mockaspsp
+
+import (
+	"crypto"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+// error codes of the OB Dynamic Client Registration specification
+const (
+	errorInvalidClientMetadata    = "invalid_client_metadata"
+	errorInvalidRedirectURI       = "invalid_redirect_uri"
+	errorInvalidSoftwareStatement = "invalid_software_statement"
+)
+
+var (
+	supportedTokenEndpointAuthMethods = []string{
+		"tls_client_auth",
+		"private_key_jwt",
+		"client_secret_basic",
+		"client_secret_post",
+		"self_signed_tls_client_auth",
+	}
+	supportedSigningAlgs      = []string{"PS256", "PS384", "PS512", "ES256"}
+	supportedResponseTypes    = []string{"code", "code id_token"}
+	supportedGrantTypes       = []string{"authorization_code", "client_credentials", "refresh_token"}
+	supportedApplicationTypes = []string{"web", "mobile"}
+)
+
+type registrationError struct {
+	code        string
+	description string
+}
+
+func (e registrationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.code, e.description)
+}
+
+func invalidClientMetadata(format string, args ...interface{}) registrationError {
+	return registrationError{code: errorInvalidClientMetadata, description: fmt.Sprintf(format, args...)}
+}
+
+func invalidSoftwareStatement(reason string) registrationError {
+	return registrationError{
+		code:        errorInvalidSoftwareStatement,
+		description: "Registration Request contains an invalid software_statement, " + reason,
+	}
+}
+
+func invalidRegistrationJWT(reason string) registrationError {
+	return invalidClientMetadata("Registration Request JWT is invalid: %s", reason)
+}
+
+type registrationClaims struct {
+	jwt.StandardClaims
+	ClientId                    string   `json:"client_id,omitempty"`
+	RedirectURIs                []string `json:"redirect_uris"`
+	TokenEndpointAuthMethod     string   `json:"token_endpoint_auth_method"`
+	TokenEndpointAuthSigningAlg string   `json:"token_endpoint_auth_signing_alg,omitempty"`
+	GrantTypes                  []string `json:"grant_types"`
+	ResponseTypes               []string `json:"response_types,omitempty"`
+	SoftwareStatement           string   `json:"software_statement"`
+	Scope                       string   `json:"scope"`
+	ApplicationType             string   `json:"application_type"`
+	IdTokenSignedResponseAlg    string   `json:"id_token_signed_response_alg"`
+	RequestObjectSigningAlg     string   `json:"request_object_signing_alg"`
+	TLSClientAuthSubjectDn      string   `json:"tls_client_auth_subject_dn,omitempty"`
+	Jwks                        *jwks    `json:"jwks,omitempty"`
+}
+
+type softwareStatementClaims struct {
+	jwt.StandardClaims
+	SoftwareId           string   `json:"software_id"`
+	SoftwareClientName   string   `json:"software_client_name,omitempty"`
+	SoftwareRoles        []string `json:"software_roles,omitempty"`
+	SoftwareRedirectURIs []string `json:"software_redirect_uris"`
+	SoftwareJwksEndpoint string   `json:"software_jwks_endpoint,omitempty"`
+	OrgId                string   `json:"org_id,omitempty"`
+	OrgName              string   `json:"org_name,omitempty"`
+}
+
+// clientMetadata is the registration response body
+type clientMetadata struct {
+	ClientId                    string   `json:"client_id"`
+	ClientSecret                string   `json:"client_secret,omitempty"`
+	ClientIdIssuedAt            int64    `json:"client_id_issued_at"`
+	RegistrationAccessToken     string   `json:"registration_access_token,omitempty"`
+	RedirectURIs                []string `json:"redirect_uris"`
+	TokenEndpointAuthMethod     string   `json:"token_endpoint_auth_method"`
+	TokenEndpointAuthSigningAlg string   `json:"token_endpoint_auth_signing_alg,omitempty"`
+	GrantTypes                  []string `json:"grant_types"`
+	ResponseTypes               []string `json:"response_types,omitempty"`
+	SoftwareId                  string   `json:"software_id"`
+	Scope                       string   `json:"scope"`
+	SoftwareStatement           string   `json:"software_statement"`
+	ApplicationType             string   `json:"application_type"`
+	IdTokenSignedResponseAlg    string   `json:"id_token_signed_response_alg"`
+	RequestObjectSigningAlg     string   `json:"request_object_signing_alg"`
+	TLSClientAuthSubjectDn      string   `json:"tls_client_auth_subject_dn,omitempty"`
+	Jwks                        *jwks    `json:"jwks,omitempty"`
+}
+
+// jwks is the client key set, self_signed_tls_client_auth clients register their certificate in x5c
+type jwks struct {
+	Keys []struct {
+		X5c []string `json:"x5c"`
+	} `json:"keys"`
+}
+
+// hasCertificate checks the certificate is the first x5c certificate of a key
+func (k *jwks) hasCertificate(cert *x509.Certificate) bool {
+	if k == nil || cert == nil {
+		return false
+	}
+	for _, key := range k.Keys {
+		if len(key.X5c) > 0 && key.X5c[0] == base64.StdEncoding.EncodeToString(cert.Raw) {
+			return true
+		}
+	}
+	return false
+}
+
+type registrationRequest struct {
+	claims     registrationClaims
+	ssa        softwareStatementClaims
+	signingKey crypto.PublicKey
+}
+
+func (r registrationRequest) metadata() clientMetadata {
+	return clientMetadata{
+		RedirectURIs:                r.claims.RedirectURIs,
+		TokenEndpointAuthMethod:     r.claims.TokenEndpointAuthMethod,
+		TokenEndpointAuthSigningAlg: r.claims.TokenEndpointAuthSigningAlg,
+		GrantTypes:                  r.claims.GrantTypes,
+		ResponseTypes:               r.claims.ResponseTypes,
+		SoftwareId:                  r.ssa.SoftwareId,
+		Scope:                       r.claims.Scope,
+		SoftwareStatement:           r.claims.SoftwareStatement,
+		ApplicationType:             r.claims.ApplicationType,
+		IdTokenSignedResponseAlg:    r.claims.IdTokenSignedResponseAlg,
+		RequestObjectSigningAlg:     r.claims.RequestObjectSigningAlg,
+		TLSClientAuthSubjectDn:      r.claims.TLSClientAuthSubjectDn,
+		Jwks:                        r.claims.Jwks,
+	}
+}
+
+// parseRegistrationRequest verifies the software statement, then the registration request JWT signed with one
+// of the software keys, then validates the client metadata against the software statement
+func (s *Server) parseRegistrationRequest(body string, r *http.Request) (registrationRequest, error) {
+	var claims registrationClaims
+	token, parts, err := new(jwt.Parser).ParseUnverified(strings.TrimSpace(body), &claims)
+	if err != nil {
+		return registrationRequest{}, invalidRegistrationJWT("request is not an encoded JWT")
+	}
+
+	ssa, err := s.verifySoftwareStatement(claims.SoftwareStatement)
+	if err != nil {
+		return registrationRequest{}, err
+	}
+
+	if !contains(supportedSigningAlgs, token.Method.Alg()) {
+		return registrationRequest{}, invalidRegistrationJWT(
+			fmt.Sprintf("signing algorithm %s is not supported", token.Method.Alg()),
+		)
+	}
+	kid, _ := token.Header["kid"].(string)
+	signingKey, ok := s.cfg.SigningKeys[kid]
+	if !ok {
+		return registrationRequest{}, invalidRegistrationJWT(fmt.Sprintf("no software signing key with kid %q", kid))
+	}
+	if err = s.verify(token, parts, signingKey); err != nil {
+		return registrationRequest{}, invalidRegistrationJWT("Expected JWT to have a valid signature")
+	}
+
+	if err = s.validateRegistrationClaims(claims, ssa, r); err != nil {
+		return registrationRequest{}, err
+	}
+
+	return registrationRequest{claims: claims, ssa: ssa, signingKey: signingKey}, nil
+}
+
+func (s *Server) verifySoftwareStatement(softwareStatement string) (softwareStatementClaims, error) {
+	if strings.Count(softwareStatement, ".") != 2 {
+		return softwareStatementClaims{}, invalidSoftwareStatement("software_statement claim is not an encoded JWT")
+	}
+
+	var claims softwareStatementClaims
+	token, parts, err := new(jwt.Parser).ParseUnverified(softwareStatement, &claims)
+	if err != nil {
+		return softwareStatementClaims{}, invalidSoftwareStatement("software_statement claim is not an encoded JWT")
+	}
+	if !contains(supportedSigningAlgs, token.Method.Alg()) {
+		return softwareStatementClaims{}, invalidSoftwareStatement(
+			fmt.Sprintf("signing algorithm %s is not supported", token.Method.Alg()),
+		)
+	}
+	if err = s.verify(token, parts, s.cfg.SoftwareStatementKey); err != nil {
+		return softwareStatementClaims{}, invalidSoftwareStatement("Expected JWT to have a valid signature")
+	}
+	if claims.SoftwareId == "" {
+		return softwareStatementClaims{}, invalidSoftwareStatement("software_id is missing")
+	}
+	return claims, nil
+}
+
+func (s *Server) validateRegistrationClaims(
+	claims registrationClaims,
+	ssa softwareStatementClaims,
+	r *http.Request,
+) error {
+	now := time.Now().Unix()
+	expired := claims.ExpiresAt < now && !s.fault(FaultAcceptExpiredJWT)
+	if claims.ExpiresAt == 0 || expired {
+		return invalidRegistrationJWT("exp is missing or the JWT has expired")
+	}
+	if claims.IssuedAt == 0 || claims.IssuedAt > now+60 {
+		return invalidRegistrationJWT("iat is missing or in the future")
+	}
+	if claims.Id == "" {
+		return invalidRegistrationJWT("jti is missing")
+	}
+	if claims.Audience != s.cfg.Audience {
+		return invalidRegistrationJWT(fmt.Sprintf("aud must be %s", s.cfg.Audience))
+	}
+	if claims.Issuer != ssa.SoftwareId {
+		return invalidRegistrationJWT("iss must be the software_id of the software_statement")
+	}
+
+	if len(claims.RedirectURIs) == 0 || !subset(claims.RedirectURIs, ssa.SoftwareRedirectURIs) {
+		return registrationError{
+			code: errorInvalidRedirectURI,
+			description: "invalid registration request redirect_uris value, " +
+				"must match or be a subset of the software_redirect_uris",
+		}
+	}
+
+	if !contains(supportedTokenEndpointAuthMethods, claims.TokenEndpointAuthMethod) {
+		return invalidClientMetadata("token_endpoint_auth_method %q is not supported", claims.TokenEndpointAuthMethod)
+	}
+	if claims.TokenEndpointAuthMethod == "private_key_jwt" &&
+		!contains(supportedSigningAlgs, claims.TokenEndpointAuthSigningAlg) {
+		return invalidClientMetadata(
+			"token_endpoint_auth_signing_alg %q is not supported", claims.TokenEndpointAuthSigningAlg,
+		)
+	}
+	if claims.TokenEndpointAuthMethod == "tls_client_auth" {
+		if claims.TLSClientAuthSubjectDn == "" {
+			return invalidClientMetadata("tls_client_auth_subject_dn is required for tls_client_auth")
+		}
+		if claims.TLSClientAuthSubjectDn != subjectDn(r) {
+			return invalidClientMetadata("tls_client_auth_subject_dn must match the transport certificate")
+		}
+	}
+
+	// jwks_uri can't be resolved by the mock, the client certificate must be registered in the jwks
+	if claims.TokenEndpointAuthMethod == "self_signed_tls_client_auth" &&
+		!claims.Jwks.hasCertificate(clientCertificate(r)) {
+		return invalidClientMetadata("jwks must hold the client certificate for self_signed_tls_client_auth")
+	}
+
+	if len(claims.GrantTypes) == 0 || !subset(claims.GrantTypes, supportedGrantTypes) {
+		return invalidClientMetadata("grant_types must be a subset of %s", strings.Join(supportedGrantTypes, ", "))
+	}
+	if !subset(claims.ResponseTypes, supportedResponseTypes) {
+		return invalidClientMetadata(
+			"response_types must be a subset of %s", strings.Join(supportedResponseTypes, ", "),
+		)
+	}
+	if !contains(supportedApplicationTypes, claims.ApplicationType) {
+		return invalidClientMetadata("application_type %q is not supported", claims.ApplicationType)
+	}
+	if !contains(supportedSigningAlgs, claims.IdTokenSignedResponseAlg) {
+		return invalidClientMetadata("id_token_signed_response_alg %q is not supported", claims.IdTokenSignedResponseAlg)
+	}
+	if !contains(supportedSigningAlgs, claims.RequestObjectSigningAlg) {
+		return invalidClientMetadata("request_object_signing_alg %q is not supported", claims.RequestObjectSigningAlg)
+	}
+	if claims.Scope == "" {
+		return invalidClientMetadata("scope is required")
+	}
+	return nil
+}
+
+func (s *Server) verify(token *jwt.Token, parts []string, key crypto.PublicKey) error {
+	if s.fault(FaultIgnoreSignature) {
+		return nil
+	}
+	return token.Method.Verify(strings.Join(parts[0:2], "."), parts[2], key)
+}
+
+// clientCertificate is the certificate the client authenticated the TLS connection with
+func clientCertificate(r *http.Request) *x509.Certificate {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return nil
+	}
+	return r.TLS.PeerCertificates[0]
+}
+
+// subjectDn is the subject DN of the client certificate in the format of the tls_client_auth_subject_dn claim
+func subjectDn(r *http.Request) string {
+	cert := clientCertificate(r)
+	if cert == nil {
+		return ""
+	}
+	return cert.Subject.ToRDNSequence().String()
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+func subset(values, of []string) bool {
+	for _, value := range values {
+		if !contains(of, value) {
+			return false
+		}
+	}
+	return true
+}
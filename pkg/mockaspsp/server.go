@@ -0,0 +1,321 @@
+package mockaspsp
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	wellKnownPath    = "/.well-known/openid-configuration"
+	registrationPath = "/register"
+	tokenPath        = "/token"
+)
+
+// Config holds the keys the mock ASPSP trusts
+type Config struct {
+	// Audience is the ASPSP identifier registration requests must be addressed to
+	Audience string
+	// SoftwareStatementKey verifies the software statements, it stands in for the OB Directory signing key
+	SoftwareStatementKey *rsa.PublicKey
+	// SigningKeys verify registration requests by kid, they stand in for the keys published at the
+	// software_jwks_endpoint of a software statement
+	SigningKeys map[string]crypto.PublicKey
+	// Faults make the mock misbehave, none by default
+	Faults []Fault
+	// SlowResponseDelay is the delay of FaultSlowResponses, DefaultSlowResponseDelay when zero
+	SlowResponseDelay time.Duration
+}
+
+// NewServer creates a mock ASPSP implementing the OB Dynamic Client Registration endpoints,
+// software clients are kept in memory
+func NewServer(cfg Config) *Server {
+	return &Server{
+		cfg:     cfg,
+		clients: map[string]registeredClient{},
+		mutex:   &sync.Mutex{},
+	}
+}
+
+type Server struct {
+	cfg     Config
+	clients map[string]registeredClient
+	mutex   *sync.Mutex
+}
+
+type registeredClient struct {
+	metadata                clientMetadata
+	registrationAccessToken string
+	signingKey              crypto.PublicKey
+}
+
+// TLSConfig requests client certificates signed by `clientCAs`, the discovery endpoint can be called without one
+// but the registration and token endpoints reject requests without a verified client certificate
+func TLSConfig(serverCert tls.Certificate, clientCAs *x509.CertPool) *tls.Config {
+	return &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		ClientAuth:   tls.VerifyClientCertIfGiven,
+		ClientCAs:    clientCAs,
+		MinVersion:   tls.VersionTLS12,
+	}
+}
+
+// RegisteredClients is the number of software clients registered and not deleted yet
+func (s *Server) RegisteredClients() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.clients)
+}
+
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(wellKnownPath, s.wellKnown)
+	mux.HandleFunc(registrationPath, s.requireClientCert(s.register))
+	mux.HandleFunc(registrationPath+"/", s.requireClientCert(s.registration))
+	mux.HandleFunc(tokenPath, s.requireClientCert(s.token))
+	return interactionId(s.injectFaults(mux))
+}
+
+func (s *Server) wellKnown(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	baseURL := "https://" + r.Host
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"issuer":                                baseURL,
+		"registration_endpoint":                 baseURL + registrationPath,
+		"token_endpoint":                        baseURL + tokenPath,
+		"token_endpoint_auth_methods_supported": supportedTokenEndpointAuthMethods,
+		"token_endpoint_auth_signing_alg_values_supported": supportedSigningAlgs,
+		"request_object_signing_alg_values_supported":      supportedSigningAlgs,
+		"id_token_signing_alg_values_supported":            supportedSigningAlgs,
+		"response_types_supported":                         supportedResponseTypes,
+		"grant_types_supported":                            supportedGrantTypes,
+		"tls_client_certificate_bound_access_tokens":       true,
+	})
+}
+
+// register handles POST /register
+func (s *Server) register(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "unable to read registration request")
+		return
+	}
+	request, err := s.parseRegistrationRequest(string(body), r)
+	if err != nil {
+		s.writeRegistrationError(w, err, string(body))
+		return
+	}
+
+	metadata := request.metadata()
+	metadata.ClientId = uuid.New().String()
+	metadata.ClientIdIssuedAt = time.Now().Unix()
+	if strings.HasPrefix(metadata.TokenEndpointAuthMethod, "client_secret") {
+		metadata.ClientSecret = clientSecret()
+	}
+	client := registeredClient{
+		metadata:                metadata,
+		registrationAccessToken: randomToken(),
+		signingKey:              request.signingKey,
+	}
+
+	s.mutex.Lock()
+	s.clients[metadata.ClientId] = client
+	s.mutex.Unlock()
+
+	statusCode := http.StatusCreated
+	if s.fault(FaultCreatedAsOK) {
+		statusCode = http.StatusOK
+	}
+	writeJSON(w, statusCode, s.clientResponse(client))
+}
+
+// registration handles GET, PUT and DELETE /register/{ClientId}
+func (s *Server) registration(w http.ResponseWriter, r *http.Request) {
+	clientId := strings.TrimPrefix(r.URL.Path, registrationPath+"/")
+	client, ok := s.authorisedClient(r, clientId)
+	if !ok {
+		description := "unknown software client or invalid registration access token"
+		writeError(w, http.StatusUnauthorized, "invalid_token", description)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.clientResponse(client))
+	case http.MethodPut:
+		s.update(w, r, client)
+	case http.MethodDelete:
+		s.mutex.Lock()
+		delete(s.clients, clientId)
+		s.mutex.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) update(w http.ResponseWriter, r *http.Request, client registeredClient) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "unable to read registration request")
+		return
+	}
+	request, err := s.parseRegistrationRequest(string(body), r)
+	if err != nil {
+		s.writeRegistrationError(w, err, string(body))
+		return
+	}
+	if request.claims.ClientId != client.metadata.ClientId {
+		writeError(w, http.StatusBadRequest, errorInvalidClientMetadata, "client_id must match the software client updated")
+		return
+	}
+
+	metadata := request.metadata()
+	metadata.ClientId = client.metadata.ClientId
+	metadata.ClientIdIssuedAt = client.metadata.ClientIdIssuedAt
+	if strings.HasPrefix(metadata.TokenEndpointAuthMethod, "client_secret") {
+		metadata.ClientSecret = client.metadata.ClientSecret
+		if metadata.ClientSecret == "" {
+			metadata.ClientSecret = clientSecret()
+		}
+	}
+	client.metadata = metadata
+	client.signingKey = request.signingKey
+
+	s.mutex.Lock()
+	s.clients[metadata.ClientId] = client
+	s.mutex.Unlock()
+
+	writeJSON(w, http.StatusOK, s.clientResponse(client))
+}
+
+// authorisedClient finds a software client by id when the request has its registration access token
+func (s *Server) authorisedClient(r *http.Request, clientId string) (registeredClient, bool) {
+	client, ok := s.client(clientId)
+	if !ok {
+		return registeredClient{}, false
+	}
+	return client, r.Header.Get("Authorization") == "Bearer "+client.registrationAccessToken
+}
+
+// token handles client credentials grants authenticated with the registered token endpoint auth method
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request", "token request must be form encoded")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "client_credentials" {
+		writeError(w, http.StatusBadRequest, "unsupported_grant_type", "only client_credentials grants are supported")
+		return
+	}
+
+	if err := s.authenticateClient(r); err != nil {
+		writeError(w, http.StatusUnauthorized, "invalid_client", err.Error())
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": randomToken(),
+		"token_type":   "Bearer",
+		"expires_in":   3600,
+	})
+}
+
+func (s *Server) client(clientId string) (registeredClient, bool) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	client, ok := s.clients[clientId]
+	return client, ok
+}
+
+func (s *Server) clientResponse(client registeredClient) clientMetadata {
+	response := client.metadata
+	response.RegistrationAccessToken = client.registrationAccessToken
+	if s.fault(FaultOmitClientId) {
+		response.ClientId = ""
+	}
+	return response
+}
+
+// requireClientCert rejects requests without a client certificate verified against the client CAs
+func (s *Server) requireClientCert(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+			writeError(w, http.StatusUnauthorized, "invalid_client", "a client certificate is required")
+			return
+		}
+		next(w, r)
+	}
+}
+
+// interactionId echoes the x-fapi-interaction-id of a request or assigns a new one
+func interactionId(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get("x-fapi-interaction-id")
+		if id == "" {
+			id = uuid.New().String()
+		}
+		w.Header().Set("x-fapi-interaction-id", id)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an OAuth error response, only `error` and `error_description` are allowed in the body
+func writeError(w http.ResponseWriter, statusCode int, code, description string) {
+	writeJSON(w, statusCode, errorBody(code, description))
+}
+
+func errorBody(code, description string) map[string]string {
+	return map[string]string{
+		"error":             code,
+		"error_description": description,
+	}
+}
+
+func (s *Server) writeRegistrationError(w http.ResponseWriter, err error, request string) {
+	body := errorBody("invalid_request", fmt.Sprintf("invalid registration request: %s", err))
+	if regErr, ok := err.(registrationError); ok {
+		body = errorBody(regErr.code, regErr.description)
+	}
+	if s.fault(FaultLeakSecrets) {
+		body["registration_request"] = request
+	}
+	writeJSON(w, http.StatusBadRequest, body)
+}
+
+func randomToken() string {
+	return strings.ReplaceAll(uuid.New().String()+uuid.New().String(), "-", "")
+}
+
+// clientSecret fits the 36 characters the OB schema allows for client_secret
+func clientSecret() string {
+	return uuid.New().String()
+}
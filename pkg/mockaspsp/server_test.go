@@ -0,0 +1,200 @@
+package mockaspsp
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServer_WellKnown(t *testing.T) {
+	env, server := startServer(t)
+	defer server.Close()
+
+	res, err := mtlsClient(t, env).Get(server.URL + wellKnownPath)
+	require.NoError(t, err)
+
+	var config map[string]interface{}
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&config))
+	assert.Equal(t, server.URL+"/register", config["registration_endpoint"])
+	assert.Equal(t, server.URL+"/token", config["token_endpoint"])
+	assert.Equal(
+		t,
+		[]interface{}{"PS256", "PS384", "PS512", "ES256"},
+		config["token_endpoint_auth_signing_alg_values_supported"],
+	)
+}
+
+func TestServer_Register_RejectsInvalidRequests(t *testing.T) {
+	env, server := startServer(t)
+	defer server.Close()
+
+	testCases := []struct {
+		name         string
+		claims       func(claims jwt.MapClaims)
+		method       jwt.SigningMethod
+		expectedCode string
+	}{
+		{
+			name:         "wrong audience",
+			claims:       func(claims jwt.MapClaims) { claims["aud"] = "another-aspsp" },
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name:         "issuer is not the software id",
+			claims:       func(claims jwt.MapClaims) { claims["iss"] = "foo.is/invalid" },
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name:         "missing jti",
+			claims:       func(claims jwt.MapClaims) { delete(claims, "jti") },
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name:         "unsupported response types",
+			claims:       func(claims jwt.MapClaims) { claims["response_types"] = []string{"token"} },
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name:         "subject dn of another certificate",
+			claims:       func(claims jwt.MapClaims) { claims["tls_client_auth_subject_dn"] = "CN=other" },
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name:         "redirect uri not in software statement",
+			claims:       func(claims jwt.MapClaims) { claims["redirect_uris"] = []string{"https://abc.com"} },
+			expectedCode: errorInvalidRedirectURI,
+		},
+		{
+			name:         "unsupported signing algorithm",
+			method:       jwt.SigningMethodRS256,
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name: "self signed tls client auth without the client certificate in jwks",
+			claims: func(claims jwt.MapClaims) {
+				claims["token_endpoint_auth_method"] = "self_signed_tls_client_auth"
+				claims["jwks"] = map[string]interface{}{"keys": []interface{}{map[string]interface{}{"x5c": []string{"MII="}}}}
+			},
+			expectedCode: errorInvalidClientMetadata,
+		},
+		{
+			name:         "software statement is not a jwt",
+			claims:       func(claims jwt.MapClaims) { claims["software_statement"] = "ssa" },
+			expectedCode: errorInvalidSoftwareStatement,
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			res := register(t, env, server.URL, registrationJWT(t, env, tc.method, tc.claims))
+
+			assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+			var body map[string]string
+			require.NoError(t, json.NewDecoder(res.Body).Decode(&body))
+			assert.Equal(t, tc.expectedCode, body["error"])
+			assert.Len(t, body, 2)
+		})
+	}
+}
+
+func TestServer_Update_RejectsAnotherClientId(t *testing.T) {
+	env, server := startServer(t)
+	defer server.Close()
+
+	res := register(t, env, server.URL, registrationJWT(t, env, nil, nil))
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+	var registered clientMetadata
+	require.NoError(t, json.NewDecoder(res.Body).Decode(&registered))
+
+	body := registrationJWT(t, env, nil, func(claims jwt.MapClaims) { claims["client_id"] = "another-client" })
+	req, err := http.NewRequest(http.MethodPut, server.URL+"/register/"+registered.ClientId, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer "+registered.RegistrationAccessToken)
+	res, err = mtlsClient(t, env).Do(req)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusBadRequest, res.StatusCode)
+}
+
+func TestServer_Token_RejectsUnregisteredClient(t *testing.T) {
+	env, server := startServer(t)
+	defer server.Close()
+
+	form := url.Values{"grant_type": {"client_credentials"}, "client_id": {"unknown"}}
+	res, err := mtlsClient(t, env).PostForm(server.URL+tokenPath, form)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+}
+
+func startServer(t *testing.T) (Environment, *httptest.Server) {
+	env, err := NewEnvironment()
+	require.NoError(t, err)
+	server := httptest.NewUnstartedServer(NewServer(env.ServerConfig()).Handler())
+	server.TLS = env.TLSConfig()
+	server.StartTLS()
+	return env, server
+}
+
+func mtlsClient(t *testing.T, env Environment) *http.Client {
+	cert, err := tls.X509KeyPair([]byte(env.TransportCertPEM), []byte(env.TransportKeyPEM))
+	require.NoError(t, err)
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(env.caCert)
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, RootCAs: rootCAs, MinVersion: tls.VersionTLS12}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}
+}
+
+func register(t *testing.T, env Environment, serverURL, body string) *http.Response {
+	res, err := mtlsClient(t, env).Post(serverURL+registrationPath, "application/jwt", strings.NewReader(body))
+	require.NoError(t, err)
+	return res
+}
+
+// registrationJWT signs a valid tls_client_auth registration request after applying `mutate` to its claims
+func registrationJWT(t *testing.T, env Environment, method jwt.SigningMethod, mutate func(jwt.MapClaims)) string {
+	block, _ := pem.Decode([]byte(env.TransportCertPEM))
+	transportCert, err := x509.ParseCertificate(block.Bytes)
+	require.NoError(t, err)
+
+	claims := jwt.MapClaims{
+		"aud":                          env.Audience,
+		"iss":                          env.SoftwareId,
+		"iat":                          time.Now().Unix(),
+		"exp":                          time.Now().Add(time.Hour).Unix(),
+		"jti":                          uuid.New().String(),
+		"grant_types":                  []string{"client_credentials"},
+		"application_type":             "web",
+		"redirect_uris":                env.RedirectURIs,
+		"token_endpoint_auth_method":   "tls_client_auth",
+		"tls_client_auth_subject_dn":   transportCert.Subject.ToRDNSequence().String(),
+		"software_statement":           env.SSA,
+		"scope":                        "accounts openid",
+		"request_object_signing_alg":   "PS256",
+		"id_token_signed_response_alg": "PS256",
+	}
+	if mutate != nil {
+		mutate(claims)
+	}
+	if method == nil {
+		method = jwt.SigningMethodPS256
+	}
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = env.Kid
+	signed, err := token.SignedString(env.signingKey)
+	require.NoError(t, err)
+	return signed
+}
@@ -0,0 +1,89 @@
+package mockaspsp
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+)
+
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// authenticateClient authenticates a token request with the token endpoint auth method the client registered
+func (s *Server) authenticateClient(r *http.Request) error {
+	if assertion := r.PostForm.Get("client_assertion"); assertion != "" {
+		return s.authenticatePrivateKeyJwt(r, assertion)
+	}
+
+	if clientId, secret, ok := r.BasicAuth(); ok {
+		client, found := s.client(clientId)
+		if !found || client.metadata.TokenEndpointAuthMethod != "client_secret_basic" {
+			return errors.New("unknown client or client not registered for client_secret_basic")
+		}
+		if secret != client.metadata.ClientSecret {
+			return errors.New("invalid client secret")
+		}
+		return nil
+	}
+
+	if secret := r.PostForm.Get("client_secret"); secret != "" {
+		client, found := s.client(r.PostForm.Get("client_id"))
+		if !found || client.metadata.TokenEndpointAuthMethod != "client_secret_post" {
+			return errors.New("unknown client or client not registered for client_secret_post")
+		}
+		if secret != client.metadata.ClientSecret {
+			return errors.New("invalid client secret")
+		}
+		return nil
+	}
+
+	client, found := s.client(r.PostForm.Get("client_id"))
+	if found && client.metadata.TokenEndpointAuthMethod == "self_signed_tls_client_auth" {
+		if !client.metadata.Jwks.hasCertificate(clientCertificate(r)) {
+			return errors.New("client certificate doesn't match a certificate of the registered jwks")
+		}
+		return nil
+	}
+	if !found || client.metadata.TokenEndpointAuthMethod != "tls_client_auth" {
+		return errors.New("unknown client or client not registered for tls_client_auth")
+	}
+	if client.metadata.TLSClientAuthSubjectDn != subjectDn(r) {
+		return errors.New("client certificate subject DN doesn't match tls_client_auth_subject_dn")
+	}
+	return nil
+}
+
+func (s *Server) authenticatePrivateKeyJwt(r *http.Request, assertion string) error {
+	if r.PostForm.Get("client_assertion_type") != clientAssertionType {
+		return fmt.Errorf("client_assertion_type must be %s", clientAssertionType)
+	}
+
+	var claims jwt.StandardClaims
+	token, parts, err := new(jwt.Parser).ParseUnverified(assertion, &claims)
+	if err != nil {
+		return errors.New("client_assertion is not an encoded JWT")
+	}
+	client, found := s.client(claims.Subject)
+	if !found || client.metadata.TokenEndpointAuthMethod != "private_key_jwt" {
+		return errors.New("unknown client or client not registered for private_key_jwt")
+	}
+	if token.Method.Alg() != client.metadata.TokenEndpointAuthSigningAlg {
+		return fmt.Errorf("client_assertion must be signed with %s", client.metadata.TokenEndpointAuthSigningAlg)
+	}
+	if err = token.Method.Verify(strings.Join(parts[0:2], "."), parts[2], client.signingKey); err != nil {
+		return errors.New("client_assertion signature is invalid")
+	}
+	if claims.Issuer != claims.Subject {
+		return errors.New("client_assertion iss and sub must be the client_id")
+	}
+	if claims.Audience != "https://"+r.Host+tokenPath {
+		return errors.New("client_assertion aud must be the token endpoint")
+	}
+	if claims.ExpiresAt < time.Now().Unix() {
+		return errors.New("client_assertion has expired")
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/pkg/errors"
+)
+
+// file names of a PKI directory
+const (
+	MetadataFile = "pki.json"
+	SSAFile      = "ssa.jwt"
+	JWKSFile     = "jwks.json"
+)
+
+// metadata are the identifiers of a PKI directory not found in its certificates
+type metadata struct {
+	SoftwareId   string   `json:"software_id"`
+	OrgId        string   `json:"org_id"`
+	Audience     string   `json:"aud"`
+	RedirectURIs []string `json:"redirect_uris"`
+	Roles        []string `json:"software_roles"`
+}
+
+// keyPairs names the certificate and key files of each key pair, `<name>.pem` and `<name>.key`
+func (p *PKI) keyPairs() map[string]*KeyPair {
+	return map[string]*KeyPair{
+		"root-ca":    &p.RootCA,
+		"issuing-ca": &p.IssuingCA,
+		"server":     &p.Server,
+		"transport":  &p.Transport,
+		"signing":    &p.Signing,
+		"directory":  &p.Directory,
+	}
+}
+
+// Write saves the PKI to dir, creating it if needed. Certificate files include their chain, private keys
+// are only readable by the owner
+func (p PKI) Write(dir string) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return errors.Wrap(err, "writing pki")
+	}
+
+	for name, keyPair := range p.keyPairs() {
+		err := ioutil.WriteFile(filepath.Join(dir, name+".pem"), []byte(keyPair.CertPEM()), 0644) // nolint:gosec
+		if err != nil {
+			return errors.Wrap(err, "writing pki")
+		}
+		err = ioutil.WriteFile(filepath.Join(dir, name+".key"), []byte(keyPair.KeyPEM()), 0600)
+		if err != nil {
+			return errors.Wrap(err, "writing pki")
+		}
+	}
+
+	jwks, err := json.MarshalIndent(p.JWKS(), "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "writing pki")
+	}
+	meta, err := json.MarshalIndent(metadata{
+		SoftwareId:   p.SoftwareId,
+		OrgId:        p.OrgId,
+		Audience:     p.Audience,
+		RedirectURIs: p.RedirectURIs,
+		Roles:        p.Roles,
+	}, "", "  ")
+	if err != nil {
+		return errors.Wrap(err, "writing pki")
+	}
+	files := map[string][]byte{
+		JWKSFile:     jwks,
+		MetadataFile: meta,
+		SSAFile:      []byte(p.SSA),
+	}
+	for name, content := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), content, 0644); err != nil { // nolint:gosec
+			return errors.Wrap(err, "writing pki")
+		}
+	}
+	return nil
+}
+
+// Load reads a PKI written to dir
+func Load(dir string) (PKI, error) {
+	content, err := ioutil.ReadFile(filepath.Join(dir, MetadataFile))
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "loading pki")
+	}
+	var meta metadata
+	if err = json.Unmarshal(content, &meta); err != nil {
+		return PKI{}, errors.Wrap(err, "loading pki metadata")
+	}
+	p := PKI{
+		SoftwareId:   meta.SoftwareId,
+		OrgId:        meta.OrgId,
+		Audience:     meta.Audience,
+		RedirectURIs: meta.RedirectURIs,
+		Roles:        meta.Roles,
+	}
+
+	ssa, err := ioutil.ReadFile(filepath.Join(dir, SSAFile))
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "loading pki")
+	}
+	p.SSA = strings.TrimSpace(string(ssa))
+
+	for name, keyPair := range p.keyPairs() {
+		*keyPair, err = loadKeyPair(filepath.Join(dir, name))
+		if err != nil {
+			return PKI{}, errors.Wrapf(err, "loading pki %s", name)
+		}
+	}
+	return p, nil
+}
+
+func loadKeyPair(path string) (KeyPair, error) {
+	certPEM, err := ioutil.ReadFile(path + ".pem")
+	if err != nil {
+		return KeyPair{}, err
+	}
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(certPEM); block != nil; block, rest = pem.Decode(rest) {
+		cert, parseErr := x509.ParseCertificate(block.Bytes)
+		if parseErr != nil {
+			return KeyPair{}, parseErr
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return KeyPair{}, errors.Errorf("no certificate in %s.pem", path)
+	}
+
+	keyPEM, err := ioutil.ReadFile(path + ".key")
+	if err != nil {
+		return KeyPair{}, err
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(keyPEM)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return KeyPair{Cert: certs[0], Chain: certs[1:], Key: key}, nil
+}
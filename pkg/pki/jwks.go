@@ -0,0 +1,49 @@
+package pki
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"math/big"
+)
+
+// JWK is a public RSA key in JSON Web Key format, with its certificate chain
+type JWK struct {
+	Kty string   `json:"kty"`
+	Use string   `json:"use"`
+	Kid string   `json:"kid"`
+	Alg string   `json:"alg,omitempty"`
+	N   string   `json:"n"`
+	E   string   `json:"e"`
+	X5c []string `json:"x5c"`
+}
+
+// JWKS is a JSON Web Key Set
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS is the software key set the OB Directory would publish at the software_jwks_endpoint, with the signing
+// key and the transport key
+func (p PKI) JWKS() JWKS {
+	return JWKS{Keys: []JWK{
+		jwk(p.Signing, "sig", "PS256"),
+		jwk(p.Transport, "tls", ""),
+	}}
+}
+
+func jwk(keyPair KeyPair, use, alg string) JWK {
+	key := keyPair.Cert.PublicKey.(*rsa.PublicKey)
+	x5c := []string{base64.StdEncoding.EncodeToString(keyPair.Cert.Raw)}
+	for _, cert := range keyPair.Chain {
+		x5c = append(x5c, base64.StdEncoding.EncodeToString(cert.Raw))
+	}
+	return JWK{
+		Kty: "RSA",
+		Use: use,
+		Kid: keyPair.Kid(),
+		Alg: alg,
+		N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		X5c: x5c,
+	}
+}
@@ -0,0 +1,346 @@
+// Package pki generates a local test PKI standing in for the OB Directory: CAs, transport and signing certificates
+// of a TPP, and a software statement, so the tool can run against a local mock ASPSP without sandbox credentials
+package pki
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1" // nolint:gosec
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+const base62 = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// Roles are the software roles a software statement can grant
+var Roles = []string{"AISP", "PISP", "CBPII"}
+
+// Options configure the software statement and certificates of a generated PKI
+type Options struct {
+	SoftwareId   string
+	OrgId        string
+	OrgName      string
+	RedirectURIs []string
+	Roles        []string
+	// Audience is the identifier of the mock ASPSP registration requests are sent to
+	Audience string
+	// Hosts are the DNS names and IP addresses of the server certificate
+	Hosts    []string
+	Validity time.Duration
+}
+
+// DefaultOptions generates OB Directory style identifiers and a server certificate for localhost
+func DefaultOptions() Options {
+	return Options{
+		SoftwareId:   randomBase62(22),
+		OrgId:        randomBase62(18),
+		OrgName:      "Conformance DCR test TPP",
+		RedirectURIs: []string{"https://tpp.example.com/callback"},
+		Roles:        Roles,
+		Audience:     randomBase62(18),
+		Hosts:        []string{"localhost", "127.0.0.1", "::1"},
+		Validity:     365 * 24 * time.Hour,
+	}
+}
+
+func (o Options) validate() error {
+	if o.SoftwareId == "" || o.OrgId == "" || o.Audience == "" {
+		return errors.New("software id, org id and audience are required")
+	}
+	if len(o.RedirectURIs) == 0 {
+		return errors.New("at least one redirect uri is required")
+	}
+	for _, redirectURI := range o.RedirectURIs {
+		u, err := url.Parse(redirectURI)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return errors.Errorf("redirect uri %q is not an https url", redirectURI)
+		}
+	}
+	if len(o.Roles) == 0 {
+		return errors.New("at least one software role is required")
+	}
+	for _, role := range o.Roles {
+		if !contains(Roles, role) {
+			return errors.Errorf("unknown software role %q", role)
+		}
+	}
+	if len(o.Hosts) == 0 {
+		return errors.New("at least one server host is required")
+	}
+	if o.Validity <= 0 {
+		return errors.New("validity must be positive")
+	}
+	return nil
+}
+
+// KeyPair is a certificate, the intermediate CAs that issued it and its private key
+type KeyPair struct {
+	Cert  *x509.Certificate
+	Chain []*x509.Certificate
+	Key   *rsa.PrivateKey
+}
+
+// CertPEM encodes the certificate followed by its chain
+func (k KeyPair) CertPEM() string {
+	certPEM := pemEncode("CERTIFICATE", k.Cert.Raw)
+	for _, cert := range k.Chain {
+		certPEM = append(certPEM, pemEncode("CERTIFICATE", cert.Raw)...)
+	}
+	return string(certPEM)
+}
+
+func (k KeyPair) KeyPEM() string {
+	return string(pemEncode("RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(k.Key)))
+}
+
+// TLSCertificate presents the certificate with its chain
+func (k KeyPair) TLSCertificate() tls.Certificate {
+	certificate := tls.Certificate{Certificate: [][]byte{k.Cert.Raw}, PrivateKey: k.Key, Leaf: k.Cert}
+	for _, cert := range k.Chain {
+		certificate.Certificate = append(certificate.Certificate, cert.Raw)
+	}
+	return certificate
+}
+
+// Kid identifies the public key of the certificate like the OB Directory does
+func (k KeyPair) Kid() string {
+	digest := sha1.Sum(k.Cert.RawSubjectPublicKeyInfo) // nolint:gosec
+	return base64.RawURLEncoding.EncodeToString(digest[:])
+}
+
+// PKI is a root CA and an issuing CA, the server certificate of a mock ASPSP, the transport and signing
+// certificates of a TPP, and its software statement signed by a directory key
+type PKI struct {
+	SoftwareId   string
+	OrgId        string
+	Audience     string
+	RedirectURIs []string
+	Roles        []string
+	SSA          string
+
+	RootCA    KeyPair
+	IssuingCA KeyPair
+	Server    KeyPair
+	Transport KeyPair
+	Signing   KeyPair
+	Directory KeyPair
+}
+
+// Generate creates a PKI, every certificate is issued by the issuing CA except the issuing CA and the directory
+// certificate signing software statements, issued by the root CA
+func Generate(opts Options) (PKI, error) {
+	if err := opts.validate(); err != nil {
+		return PKI{}, errors.Wrap(err, "generating pki")
+	}
+	notAfter := time.Now().Add(opts.Validity)
+	p := PKI{
+		SoftwareId:   opts.SoftwareId,
+		OrgId:        opts.OrgId,
+		Audience:     opts.Audience,
+		RedirectURIs: opts.RedirectURIs,
+		Roles:        opts.Roles,
+	}
+
+	var err error
+	p.RootCA, err = newKeyPair(&x509.Certificate{
+		Subject:               obName("Conformance DCR Root CA"),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}, KeyPair{})
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "generating root CA")
+	}
+
+	p.IssuingCA, err = newKeyPair(&x509.Certificate{
+		Subject:               obName("Conformance DCR Issuing CA"),
+		NotAfter:              notAfter,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLenZero:        true,
+	}, p.RootCA)
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "generating issuing CA")
+	}
+
+	p.Directory, err = newKeyPair(&x509.Certificate{
+		Subject:  obName("Conformance DCR Directory"),
+		NotAfter: notAfter,
+		KeyUsage: x509.KeyUsageDigitalSignature,
+	}, p.RootCA)
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "generating directory certificate")
+	}
+
+	server := &x509.Certificate{
+		Subject:     pkix.Name{CommonName: opts.Hosts[0]},
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	for _, host := range opts.Hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			server.IPAddresses = append(server.IPAddresses, ip)
+		} else {
+			server.DNSNames = append(server.DNSNames, host)
+		}
+	}
+	p.Server, err = newKeyPair(server, p.IssuingCA)
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "generating server certificate")
+	}
+
+	p.Transport, err = newKeyPair(&x509.Certificate{
+		Subject:     tppName(opts),
+		NotAfter:    notAfter,
+		KeyUsage:    x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}, p.IssuingCA)
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "generating transport certificate")
+	}
+
+	p.Signing, err = newKeyPair(&x509.Certificate{
+		Subject:  tppName(opts),
+		NotAfter: notAfter,
+		KeyUsage: x509.KeyUsageDigitalSignature | x509.KeyUsageContentCommitment,
+	}, p.IssuingCA)
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "generating signing certificate")
+	}
+
+	p.SSA, err = p.softwareStatement(opts.OrgName)
+	if err != nil {
+		return PKI{}, errors.Wrap(err, "signing software statement")
+	}
+
+	return p, nil
+}
+
+// softwareStatementClaims are the claims of an OB Directory software statement
+type softwareStatementClaims struct {
+	jwt.StandardClaims
+	SoftwareEnvironment  string   `json:"software_environment"`
+	SoftwareMode         string   `json:"software_mode"`
+	SoftwareId           string   `json:"software_id"`
+	SoftwareClientId     string   `json:"software_client_id"`
+	SoftwareClientName   string   `json:"software_client_name"`
+	SoftwareRoles        []string `json:"software_roles"`
+	SoftwareRedirectURIs []string `json:"software_redirect_uris"`
+	SoftwareJwksEndpoint string   `json:"software_jwks_endpoint"`
+	OrgStatus            string   `json:"org_status"`
+	OrgId                string   `json:"org_id"`
+	OrgName              string   `json:"org_name"`
+	OrgJwksEndpoint      string   `json:"org_jwks_endpoint"`
+}
+
+func (p PKI) softwareStatement(orgName string) (string, error) {
+	keystore := "https://keystore.example.com/" + p.OrgId
+	claims := softwareStatementClaims{
+		StandardClaims: jwt.StandardClaims{
+			Issuer:   "OpenBanking Ltd",
+			IssuedAt: time.Now().Unix(),
+			Id:       uuid.New().String(),
+		},
+		SoftwareEnvironment:  "sandbox",
+		SoftwareMode:         "Test",
+		SoftwareId:           p.SoftwareId,
+		SoftwareClientId:     p.SoftwareId,
+		SoftwareClientName:   orgName,
+		SoftwareRoles:        p.Roles,
+		SoftwareRedirectURIs: p.RedirectURIs,
+		SoftwareJwksEndpoint: keystore + "/" + p.SoftwareId + ".jwks",
+		OrgStatus:            "Active",
+		OrgId:                p.OrgId,
+		OrgName:              orgName,
+		OrgJwksEndpoint:      keystore + "/" + p.OrgId + ".jwks",
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodPS256, claims)
+	token.Header["kid"] = p.Directory.Kid()
+	return token.SignedString(p.Directory.Key)
+}
+
+// obName is the subject DN of OB Directory CAs
+func obName(commonName string) pkix.Name {
+	return pkix.Name{CommonName: commonName, Organization: []string{"OpenBanking"}, Country: []string{"GB"}}
+}
+
+// tppName is the subject DN of OB Directory TPP certificates, the software id and the org id
+func tppName(opts Options) pkix.Name {
+	return pkix.Name{
+		CommonName:         opts.SoftwareId,
+		OrganizationalUnit: []string{opts.OrgId},
+		Organization:       []string{"OpenBanking"},
+		Country:            []string{"GB"},
+	}
+}
+
+// newKeyPair issues the template with the issuer, or self-signs it when the issuer has no certificate
+func newKeyPair(template *x509.Certificate, issuer KeyPair) (KeyPair, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	template.SerialNumber, err = rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 62))
+	if err != nil {
+		return KeyPair{}, err
+	}
+	template.NotBefore = time.Now().Add(-time.Hour)
+
+	parent, parentKey := template, key
+	var chain []*x509.Certificate
+	if issuer.Cert != nil {
+		parent, parentKey = issuer.Cert, issuer.Key
+		if issuer.Cert.Subject.String() != issuer.Cert.Issuer.String() {
+			chain = append([]*x509.Certificate{issuer.Cert}, issuer.Chain...)
+		}
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return KeyPair{}, err
+	}
+	return KeyPair{Cert: cert, Chain: chain, Key: key}, nil
+}
+
+func pemEncode(blockType string, der []byte) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+}
+
+// randomBase62 generates identifiers in the format the OB Directory uses for organisations and software statements
+func randomBase62(length int) string {
+	id := make([]byte, length)
+	for i := range id {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(base62))))
+		if err != nil {
+			n = big.NewInt(int64(i))
+		}
+		id[i] = base62[n.Int64()]
+	}
+	return string(id)
+}
+
+func contains(values []string, value string) bool {
+	for _, item := range values {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
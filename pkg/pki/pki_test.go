@@ -0,0 +1,157 @@
+package pki
+
+import (
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerate_IssuesCertificatesChainingToRootCA(t *testing.T) {
+	p, err := Generate(DefaultOptions())
+	require.NoError(t, err)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(p.RootCA.Cert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(p.IssuingCA.Cert)
+
+	_, err = p.Transport.Cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	assert.NoError(t, err)
+	_, err = p.Server.Cert.Verify(x509.VerifyOptions{
+		DNSName:       "localhost",
+		Roots:         roots,
+		Intermediates: intermediates,
+	})
+	assert.NoError(t, err)
+
+	expectedDn := "CN=" + p.SoftwareId + ",OU=" + p.OrgId + ",O=OpenBanking,C=GB"
+	assert.Equal(t, expectedDn, p.Transport.Cert.Subject.String())
+	assert.Equal(t, expectedDn, p.Signing.Cert.Subject.String())
+	assert.Equal(t, []*x509.Certificate{p.IssuingCA.Cert}, p.Transport.Chain)
+}
+
+func TestGenerate_SignsSoftwareStatement(t *testing.T) {
+	opts := DefaultOptions()
+	opts.SoftwareId = "software-id"
+	opts.RedirectURIs = []string{"https://tpp.com/a", "https://tpp.com/b"}
+	opts.Roles = []string{"AISP"}
+	p, err := Generate(opts)
+	require.NoError(t, err)
+
+	var claims softwareStatementClaims
+	token, err := jwt.ParseWithClaims(p.SSA, &claims, func(token *jwt.Token) (interface{}, error) {
+		return &p.Directory.Key.PublicKey, nil
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, p.Directory.Kid(), token.Header["kid"])
+	assert.Equal(t, "PS256", token.Method.Alg())
+	assert.Equal(t, "software-id", claims.SoftwareId)
+	assert.Equal(t, opts.RedirectURIs, claims.SoftwareRedirectURIs)
+	assert.Equal(t, []string{"AISP"}, claims.SoftwareRoles)
+	assert.Equal(t, p.OrgId, claims.OrgId)
+}
+
+func TestGenerate_ValidatesOptions(t *testing.T) {
+	testCases := []struct {
+		name          string
+		mutate        func(opts *Options)
+		expectedError string
+	}{
+		{
+			name:          "empty software id",
+			mutate:        func(opts *Options) { opts.SoftwareId = "" },
+			expectedError: "generating pki: software id, org id and audience are required",
+		},
+		{
+			name:          "redirect uri not https",
+			mutate:        func(opts *Options) { opts.RedirectURIs = []string{"http://tpp.com"} },
+			expectedError: `generating pki: redirect uri "http://tpp.com" is not an https url`,
+		},
+		{
+			name:          "unknown role",
+			mutate:        func(opts *Options) { opts.Roles = []string{"AISP", "TPP"} },
+			expectedError: `generating pki: unknown software role "TPP"`,
+		},
+		{
+			name:          "no hosts",
+			mutate:        func(opts *Options) { opts.Hosts = nil },
+			expectedError: "generating pki: at least one server host is required",
+		},
+	}
+
+	for _, tc := range testCases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			opts := DefaultOptions()
+			tc.mutate(&opts)
+
+			_, err := Generate(opts)
+
+			assert.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestJWKS_PublishesSigningAndTransportKeys(t *testing.T) {
+	p, err := Generate(DefaultOptions())
+	require.NoError(t, err)
+
+	jwks := p.JWKS()
+
+	require.Len(t, jwks.Keys, 2)
+	assert.Equal(t, p.Signing.Kid(), jwks.Keys[0].Kid)
+	assert.Equal(t, "sig", jwks.Keys[0].Use)
+	assert.Equal(t, "PS256", jwks.Keys[0].Alg)
+	assert.Equal(t, "AQAB", jwks.Keys[0].E)
+	assert.Len(t, jwks.Keys[0].X5c, 2)
+	assert.Equal(t, p.Transport.Kid(), jwks.Keys[1].Kid)
+	assert.Equal(t, "tls", jwks.Keys[1].Use)
+}
+
+func TestWriteLoad_RoundTrips(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pki")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	p, err := Generate(DefaultOptions())
+	require.NoError(t, err)
+
+	require.NoError(t, p.Write(filepath.Join(dir, "local")))
+	loaded, err := Load(filepath.Join(dir, "local"))
+	require.NoError(t, err)
+
+	assert.Equal(t, p.SoftwareId, loaded.SoftwareId)
+	assert.Equal(t, p.Audience, loaded.Audience)
+	assert.Equal(t, p.RedirectURIs, loaded.RedirectURIs)
+	assert.Equal(t, p.SSA, loaded.SSA)
+	assert.Equal(t, p.Transport.CertPEM(), loaded.Transport.CertPEM())
+	assert.Equal(t, p.Signing.KeyPEM(), loaded.Signing.KeyPEM())
+	assert.Equal(t, p.Directory.Kid(), loaded.Directory.Kid())
+
+	info, err := os.Stat(filepath.Join(dir, "local", "signing.key"))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "local", JWKSFile))
+	require.NoError(t, err)
+	var jwks JWKS
+	require.NoError(t, json.Unmarshal(content, &jwks))
+	assert.Equal(t, p.JWKS(), jwks)
+}
+
+func TestLoad_HandlesMissingDirectory(t *testing.T) {
+	_, err := Load("testdata/missing")
+
+	assert.Error(t, err)
+}